@@ -0,0 +1,148 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// batchPrefix marks a request as a batch of ';' separated commands
+	batchPrefix = "[[BATCH]]"
+	// batchDelimiter separates replies of a batch request
+	batchDelimiter = "\n\n\n"
+)
+
+// Batch collects dispatchers, keywords and queries and sends them to hyprland
+// in a single request. Hyprland runs the commands in order without rendering
+// in between.
+//
+// Hyprland separates the commands of a batch with ';' and has no way to escape
+// it, so commands containing ';' (e.g. Exec with a shell command list) can not
+// be batched. Send rejects such a batch without sending anything.
+type Batch struct {
+	client   *RequestClient
+	commands []batchCommand
+	// errs are errors of invalid commands, reported by Send
+	errs []error
+}
+
+type batchCommand struct {
+	// cmd is the command as sent to hyprland
+	cmd string
+	// v is where a query reply is decoded, nil for other commands
+	v any
+}
+
+// BatchResult is the result of a single command in a Batch.
+type BatchResult struct {
+	// Command is the command sent to hyprland
+	Command string
+	// Response is the raw reply of the command
+	Response string
+	// Err is the error reported by hyprland or the decode error of a query
+	Err error
+}
+
+// NewBatch creates an empty Batch that is sent through c.
+func (c *RequestClient) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Len returns the number of commands in the batch.
+func (b *Batch) Len() int {
+	return len(b.commands)
+}
+
+// Dispatch returns a Dispatcher that adds dispatchers to the batch. Invalid
+// arguments (e.g. a zero selector) are returned right away and the dispatcher
+// is not added; errors reported by hyprland are returned by Send.
+func (b *Batch) Dispatch() Dispatcher {
+	return Dispatcher{send: func(cmd string) error {
+		b.Command(cmd)
+		return nil
+	}}
+}
+
+// Keyword adds a keyword command to the batch. An empty name is reported by
+// Send.
+func (b *Batch) Keyword(name, value string) *Batch {
+	if err := checkKeywordName(name); err != nil {
+		b.errs = append(b.errs, err)
+		return b
+	}
+	return b.Command(keywordCommand(name, value))
+}
+
+// Query adds a JSON query (e.g. "clients") to the batch. The reply is decoded
+// into v when the batch is sent.
+func (b *Batch) Query(cmd string, v any) *Batch {
+	b.commands = append(b.commands, batchCommand{cmd: "j/" + cmd, v: v})
+	return b
+}
+
+// Command adds a raw command to the batch. The command must reply with "ok".
+func (b *Batch) Command(cmd string) *Batch {
+	b.commands = append(b.commands, batchCommand{cmd: cmd})
+	return b
+}
+
+// Send sends all commands in one request and splits the reply back into
+// per-command results. The returned error joins all per-command errors. Nothing
+// is sent if a command was invalid.
+func (b *Batch) Send() ([]BatchResult, error) {
+	return b.SendContext(context.Background())
+}
+
+// SendContext is like Send but respects the deadline and cancellation of ctx.
+func (b *Batch) SendContext(ctx context.Context) ([]BatchResult, error) {
+	if len(b.errs) != 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if len(b.commands) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]string, len(b.commands))
+	for i, c := range b.commands {
+		if strings.Contains(c.cmd, ";") {
+			return nil, fmt.Errorf("batch command can not contain ';': %q", c.cmd)
+		}
+		cmds[i] = c.cmd
+	}
+
+	resp, err := b.client.rawRequestContext(
+		ctx,
+		batchPrefix+strings.Join(cmds, ";"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := strings.Split(string(resp), batchDelimiter)
+	if len(replies) != len(b.commands) {
+		return nil, fmt.Errorf(
+			"expected %d batch replies, got %d",
+			len(b.commands),
+			len(replies),
+		)
+	}
+
+	results := make([]BatchResult, len(b.commands))
+	errs := make([]error, 0, len(b.commands))
+	for i, c := range b.commands {
+		r := BatchResult{Command: c.cmd, Response: replies[i]}
+		if c.v != nil {
+			r.Err = decodeResponse(c.cmd, []byte(replies[i]), c.v)
+		} else {
+			r.Err = checkResponse(c.cmd, []byte(replies[i]))
+		}
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+		results[i] = r
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package hyprland
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestBatch(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.Handle("j/clients", `[{"address":"0x1"}]`)
+	s.Handle("dispatch nope", "Invalid dispatcher")
+
+	c := NewRequestClient()
+	b := c.NewBatch()
+	var clients Clients
+	b.Dispatch().Workspace(WorkspaceByID(1))
+	b.Query("clients", &clients)
+	b.Dispatch().Raw("nope")
+
+	results, err := b.Send()
+	if err == nil {
+		t.Error("Send() should report the failed dispatcher")
+	}
+
+	want := []string{"dispatch workspace 1", "j/clients", "dispatch nope"}
+	if got := s.Commands(); !slices.Equal(got, want) {
+		t.Errorf("Send() sent %q, want %q", got, want)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Send() returned %d results, want 3", len(results))
+	}
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("unexpected errors: %v, %v", results[0].Err, results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Error("results[2].Err should not be nil")
+	}
+	if len(clients) != 1 || clients[0].Address != "0x1" {
+		t.Errorf("query decoded %+v", clients)
+	}
+}
+
+func TestBatchInvalid(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	c := NewRequestClient()
+
+	b := c.NewBatch()
+	b.Keyword("", "1")
+	if _, err := b.Send(); err == nil {
+		t.Error("Send() should reject a keyword with an empty name")
+	}
+
+	b = c.NewBatch()
+	b.Dispatch().Exec("notify-send a; notify-send b")
+	if _, err := b.Send(); err == nil {
+		t.Error("Send() should reject a command containing ';'")
+	}
+
+	if got := s.Commands(); len(got) != 0 {
+		t.Errorf("Send() sent %q for an invalid batch", got)
+	}
+}
@@ -11,42 +11,68 @@ type wanted interface {
 	int | uint | string | bool
 }
 
+// cast converts s to T. Strings are kept as is, other types ignore
+// surrounding spaces.
 func cast[T wanted](s string) (T, error) {
 	var zero T
 	switch any(zero).(type) {
 	case int:
-		v, err := strconv.Atoi(s)
+		v, err := strconv.Atoi(strings.TrimSpace(s))
 		return any(v).(T), err
 	case uint:
-		v, err := strconv.ParseUint(s, 10, 64)
+		v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
 		return any(uint(v)).(T), err
 	case string:
 		return any(s).(T), nil
 	case bool:
-		v, err := strconv.ParseBool(s)
+		v, err := strconv.ParseBool(strings.TrimSpace(s))
 		return any(v).(T), err
 	default:
 		return zero, errors.New("unsupported type")
 	}
 }
 
-// cast2 splits by ',' and converts to two generic types
-func cast2[T1, T2 wanted](s string) (T1, T2, error) {
+// splitArgs splits the comma separated arguments of an event into n fields.
+// The field at index free is free text like a window title or workspace name
+// and may contain commas: the fields before it are split off from the left
+// and the fields after it from the right, so it is kept losslessly. Only one
+// field of an event can be free.
+func splitArgs(s string, n, free int) ([]string, error) {
+	parts := make([]string, n)
+	rest := s
+	for i := range free {
+		before, after, ok := strings.Cut(rest, ",")
+		if !ok {
+			return nil, fmt.Errorf("need %d comma-separated values", n)
+		}
+		parts[i], rest = before, after
+	}
+	for i := n - 1; i > free; i-- {
+		j := strings.LastIndexByte(rest, ',')
+		if j < 0 {
+			return nil, fmt.Errorf("need %d comma-separated values", n)
+		}
+		parts[i], rest = rest[j+1:], rest[:j]
+	}
+	parts[free] = rest
+	return parts, nil
+}
+
+// cast2 splits s into two fields with splitArgs and converts them
+func cast2[T1, T2 wanted](s string, free int) (T1, T2, error) {
 	var zero1 T1
 	var zero2 T2
 
-	parts := strings.SplitN(s, ",", 2)
-	if len(parts) < 2 {
-		return zero1, zero2, errors.New(
-			"need at least 2 comma-separated values",
-		)
+	parts, err := splitArgs(s, 2, free)
+	if err != nil {
+		return zero1, zero2, err
 	}
 
-	v1, err := cast[T1](strings.TrimSpace(parts[0]))
+	v1, err := cast[T1](parts[0])
 	if err != nil {
 		return zero1, zero2, fmt.Errorf("convert 1: %w", err)
 	}
-	v2, err := cast[T2](strings.TrimSpace(parts[1]))
+	v2, err := cast[T2](parts[1])
 	if err != nil {
 		return zero1, zero2, fmt.Errorf("convert 2: %w", err)
 	}
@@ -56,28 +82,26 @@ func cast2[T1, T2 wanted](s string) (T1, T2, error) {
 
 //revive:disable:function-result-limit
 
-// cast3 splits by ',' and converts to three generic types
-func cast3[T1, T2, T3 wanted](s string) (T1, T2, T3, error) {
+// cast3 splits s into three fields with splitArgs and converts them
+func cast3[T1, T2, T3 wanted](s string, free int) (T1, T2, T3, error) {
 	var zero1 T1
 	var zero2 T2
 	var zero3 T3
 
-	parts := strings.SplitN(s, ",", 3)
-	if len(parts) < 3 {
-		return zero1, zero2, zero3, errors.New(
-			"need at least 3 comma-separated values",
-		)
+	parts, err := splitArgs(s, 3, free)
+	if err != nil {
+		return zero1, zero2, zero3, err
 	}
 
-	v1, err := cast[T1](strings.TrimSpace(parts[0]))
+	v1, err := cast[T1](parts[0])
 	if err != nil {
 		return zero1, zero2, zero3, fmt.Errorf("convert 1: %w", err)
 	}
-	v2, err := cast[T2](strings.TrimSpace(parts[1]))
+	v2, err := cast[T2](parts[1])
 	if err != nil {
 		return zero1, zero2, zero3, fmt.Errorf("convert 2: %w", err)
 	}
-	v3, err := cast[T3](strings.TrimSpace(parts[2]))
+	v3, err := cast[T3](parts[2])
 	if err != nil {
 		return zero1, zero2, zero3, fmt.Errorf("convert 3: %w", err)
 	}
@@ -85,35 +109,36 @@ func cast3[T1, T2, T3 wanted](s string) (T1, T2, T3, error) {
 	return v1, v2, v3, nil
 }
 
-// cast4 splits by ',' and converts to three generic types
-func cast4[T1, T2, T3, T4 wanted](s string) (T1, T2, T3, T4, error) {
+// cast4 splits s into four fields with splitArgs and converts them
+func cast4[T1, T2, T3, T4 wanted](
+	s string,
+	free int,
+) (T1, T2, T3, T4, error) {
 	var zero1 T1
 	var zero2 T2
 	var zero3 T3
 	var zero4 T4
 
-	parts := strings.SplitN(s, ",", 3)
-	if len(parts) < 3 {
-		return zero1, zero2, zero3, zero4, errors.New(
-			"need at least 4 comma-separated values",
-		)
+	parts, err := splitArgs(s, 4, free)
+	if err != nil {
+		return zero1, zero2, zero3, zero4, err
 	}
 
-	v1, err := cast[T1](strings.TrimSpace(parts[0]))
+	v1, err := cast[T1](parts[0])
 	if err != nil {
 		return zero1, zero2, zero3, zero4, fmt.Errorf("convert 1: %w", err)
 	}
-	v2, err := cast[T2](strings.TrimSpace(parts[1]))
+	v2, err := cast[T2](parts[1])
 	if err != nil {
 		return zero1, zero2, zero3, zero4, fmt.Errorf("convert 2: %w", err)
 	}
-	v3, err := cast[T3](strings.TrimSpace(parts[2]))
+	v3, err := cast[T3](parts[2])
 	if err != nil {
 		return zero1, zero2, zero3, zero4, fmt.Errorf("convert 3: %w", err)
 	}
-	v4, err := cast[T4](strings.TrimSpace(parts[2]))
+	v4, err := cast[T4](parts[3])
 	if err != nil {
-		return zero1, zero2, zero3, zero4, fmt.Errorf("convert 3: %w", err)
+		return zero1, zero2, zero3, zero4, fmt.Errorf("convert 4: %w", err)
 	}
 
 	return v1, v2, v3, v4, nil
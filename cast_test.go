@@ -0,0 +1,59 @@
+package hyprland
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		s       string
+		n, free int
+		want    []string
+	}{
+		{"a,b", 2, 1, []string{"a", "b"}},
+		{"1,a, b", 2, 1, []string{"1", "a, b"}},
+		{"a,b,DP-1", 2, 0, []string{"a,b", "DP-1"}},
+		{"2,a,b,DP-1", 3, 1, []string{"2", "a,b", "DP-1"}},
+		{"5a1f,1,kitty,", 4, 3, []string{"5a1f", "1", "kitty", ""}},
+		{"5a1f,1,kitty,a,b,c", 4, 3, []string{"5a1f", "1", "kitty", "a,b,c"}},
+		{",,", 3, 1, []string{"", "", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitArgs(tt.s, tt.n, tt.free)
+		if err != nil {
+			t.Errorf("splitArgs(%q, %d, %d) failed: %v", tt.s, tt.n, tt.free, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf(
+				"splitArgs(%q, %d, %d) = %q, want %q",
+				tt.s, tt.n, tt.free, got, tt.want,
+			)
+		}
+	}
+
+	if _, err := splitArgs("a,b", 3, 1); err == nil {
+		t.Error("splitArgs() with too few values should fail")
+	}
+}
+
+func FuzzSplitArgs(f *testing.F) {
+	f.Add("", "", "")
+	f.Add("kitty", "a, b", "DP-1")
+	f.Add("x", ",,,", "")
+	f.Fuzz(func(t *testing.T, first, free, last string) {
+		if strings.Contains(first, ",") || strings.Contains(last, ",") {
+			t.Skip()
+		}
+		s := first + "," + free + "," + last
+		got, err := splitArgs(s, 3, 1)
+		if err != nil {
+			t.Fatalf("splitArgs(%q) failed: %v", s, err)
+		}
+		if want := []string{first, free, last}; !slices.Equal(got, want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", s, got, want)
+		}
+	})
+}
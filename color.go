@@ -0,0 +1,56 @@
+package hyprland
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is a RGBA color as understood by hyprland config values.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// RGB returns an opaque Color.
+func RGB(r, g, b uint8) Color {
+	return Color{R: r, G: g, B: b, A: 0xff}
+}
+
+// RGBA returns a Color with alpha.
+func RGBA(r, g, b, a uint8) Color {
+	return Color{R: r, G: g, B: b, A: a}
+}
+
+// String returns the color in hyprland's rgba(RRGGBBAA) syntax, or
+// rgb(RRGGBB) when the color is opaque.
+func (c Color) String() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("rgb(%02x%02x%02x)", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgba(%02x%02x%02x%02x)", c.R, c.G, c.B, c.A)
+}
+
+// Hex returns the color in hyprland's legacy 0xAARRGGBB syntax.
+func (c Color) Hex() string {
+	return fmt.Sprintf("0x%02x%02x%02x%02x", c.A, c.R, c.G, c.B)
+}
+
+// Gradient is a list of colors with an optional angle in degrees. It is used
+// by options like general:col.active_border.
+type Gradient struct {
+	Colors []Color
+	Angle  int
+}
+
+// String returns the gradient in hyprland's config syntax, e.g.
+// "rgb(ff0000) rgb(0000ff) 45deg".
+func (g Gradient) String() string {
+	parts := make([]string, 0, len(g.Colors)+1)
+	for _, c := range g.Colors {
+		parts = append(parts, c.String())
+	}
+	if g.Angle != 0 {
+		parts = append(parts, strconv.Itoa(g.Angle)+"deg")
+	}
+	return strings.Join(parts, " ")
+}
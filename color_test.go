@@ -0,0 +1,29 @@
+package hyprland
+
+import "testing"
+
+func TestColor(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"RGB.String", RGB(0xff, 0x1e, 0xa3).String(), "rgb(ff1ea3)"},
+		{"RGBA.String", RGBA(1, 2, 3, 4).String(), "rgba(01020304)"},
+		{"RGBA.Hex", RGBA(1, 2, 3, 4).Hex(), "0x04010203"},
+		{
+			"Gradient.String",
+			Gradient{Colors: []Color{RGB(255, 0, 0), RGB(0, 0, 255)}, Angle: 45}.
+				String(),
+			"rgb(ff0000) rgb(0000ff) 45deg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
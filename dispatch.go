@@ -0,0 +1,251 @@
+package hyprland
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Dispatcher sends hyprland dispatchers ('dispatch <name> <args>') over the
+// request socket. Use RequestClient.Dispatch to get one.
+type Dispatcher struct {
+	// send writes a full command to hyprland and checks the reply
+	send func(cmd string) error
+}
+
+// Dispatch returns a Dispatcher that sends dispatchers through c.
+func (c *RequestClient) Dispatch() Dispatcher {
+	return c.DispatchContext(context.Background())
+}
+
+// DispatchContext returns a Dispatcher whose requests are aborted when ctx is
+// done.
+func (c *RequestClient) DispatchContext(ctx context.Context) Dispatcher {
+	return Dispatcher{send: func(cmd string) error {
+		return c.commandContext(ctx, cmd)
+	}}
+}
+
+// FullscreenMode is the mode argument of the fullscreen dispatcher.
+type FullscreenMode int
+
+const (
+	// FullscreenFull makes the window take the whole monitor
+	FullscreenFull FullscreenMode = 0
+	// FullscreenMaximize maximizes the window while keeping gaps and bars
+	FullscreenMaximize FullscreenMode = 1
+)
+
+// Direction is a direction argument used by dispatchers like movefocus.
+type Direction string
+
+const (
+	// DirectionLeft is left
+	DirectionLeft Direction = "l"
+	// DirectionRight is right
+	DirectionRight Direction = "r"
+	// DirectionUp is up
+	DirectionUp Direction = "u"
+	// DirectionDown is down
+	DirectionDown Direction = "d"
+)
+
+// Raw sends dispatcher with args as is. Use this for dispatchers that does not
+// have a typed method.
+func (d Dispatcher) Raw(dispatcher string, args ...string) error {
+	cmd := "dispatch " + dispatcher
+	if arg := strings.Join(args, " "); arg != "" {
+		cmd += " " + arg
+	}
+	return d.send(cmd)
+}
+
+// Exec executes a shell command.
+func (d Dispatcher) Exec(command string) error {
+	return d.Raw("exec", command)
+}
+
+// Workspace changes the active workspace.
+func (d Dispatcher) Workspace(workspace WorkspaceSelector) error {
+	ws, err := workspace.arg()
+	if err != nil {
+		return err
+	}
+	return d.Raw("workspace", ws)
+}
+
+// FocusWindow focuses window.
+func (d Dispatcher) FocusWindow(window WindowSelector) error {
+	if window.IsZero() {
+		return errNoWindow
+	}
+	return d.rawWindow("focuswindow", "", window)
+}
+
+// FocusMonitor focuses the given monitor.
+func (d Dispatcher) FocusMonitor(monitor string) error {
+	return d.Raw("focusmonitor", monitor)
+}
+
+// MoveFocus moves the focus to the given direction.
+func (d Dispatcher) MoveFocus(dir Direction) error {
+	return d.Raw("movefocus", string(dir))
+}
+
+// MoveWindow moves the active window to the given direction.
+func (d Dispatcher) MoveWindow(dir Direction) error {
+	return d.Raw("movewindow", string(dir))
+}
+
+// SwapWindow swaps the active window with the window in the given direction.
+func (d Dispatcher) SwapWindow(dir Direction) error {
+	return d.Raw("swapwindow", string(dir))
+}
+
+// MoveToWorkspace moves window to workspace and follows it. The zero window
+// means the active window.
+func (d Dispatcher) MoveToWorkspace(
+	workspace WorkspaceSelector,
+	window WindowSelector,
+) error {
+	ws, err := workspace.arg()
+	if err != nil {
+		return err
+	}
+	return d.rawWindow("movetoworkspace", ws, window)
+}
+
+// MoveToWorkspaceSilent moves window to workspace without following it. The
+// zero window means the active window.
+func (d Dispatcher) MoveToWorkspaceSilent(
+	workspace WorkspaceSelector,
+	window WindowSelector,
+) error {
+	ws, err := workspace.arg()
+	if err != nil {
+		return err
+	}
+	return d.rawWindow("movetoworkspacesilent", ws, window)
+}
+
+// ToggleSpecialWorkspace toggles the special workspace with the given name.
+// Empty name means the default special workspace.
+func (d Dispatcher) ToggleSpecialWorkspace(name string) error {
+	return d.Raw("togglespecialworkspace", name)
+}
+
+// ToggleFloating toggles floating state of window. The zero window means the
+// active window.
+func (d Dispatcher) ToggleFloating(window WindowSelector) error {
+	return d.rawWindow("togglefloating", "", window)
+}
+
+// SetFloating makes window float. The zero window means the active window.
+func (d Dispatcher) SetFloating(window WindowSelector) error {
+	return d.rawWindow("setfloating", "", window)
+}
+
+// SetTiled makes window tiled. The zero window means the active window.
+func (d Dispatcher) SetTiled(window WindowSelector) error {
+	return d.rawWindow("settiled", "", window)
+}
+
+// Pseudo toggles pseudo tiling of window. The zero window means the active
+// window.
+func (d Dispatcher) Pseudo(window WindowSelector) error {
+	return d.rawWindow("pseudo", "", window)
+}
+
+// Pin pins window to be visible on all workspaces. Only works on floating
+// windows. The zero window means the active window.
+func (d Dispatcher) Pin(window WindowSelector) error {
+	return d.rawWindow("pin", "", window)
+}
+
+// Fullscreen toggles fullscreen of the active window.
+func (d Dispatcher) Fullscreen(mode FullscreenMode) error {
+	return d.Raw("fullscreen", strconv.Itoa(int(mode)))
+}
+
+// CenterWindow centers the active floating window.
+func (d Dispatcher) CenterWindow() error {
+	return d.Raw("centerwindow")
+}
+
+// KillActive closes the active window.
+func (d Dispatcher) KillActive() error {
+	return d.Raw("killactive")
+}
+
+// CloseWindow closes window.
+func (d Dispatcher) CloseWindow(window WindowSelector) error {
+	if window.IsZero() {
+		return errNoWindow
+	}
+	return d.rawWindow("closewindow", "", window)
+}
+
+// ResizeActive resizes the active window. When exact is false x and y are
+// relative to the current size.
+func (d Dispatcher) ResizeActive(x, y int, exact bool) error {
+	return d.Raw("resizeactive", formatVec(x, y, exact))
+}
+
+// MoveActive moves the active window. When exact is false x and y are relative
+// to the current position.
+func (d Dispatcher) MoveActive(x, y int, exact bool) error {
+	return d.Raw("moveactive", formatVec(x, y, exact))
+}
+
+// ResizeWindowPixel resizes window. When exact is false x and y are relative
+// to the current size.
+func (d Dispatcher) ResizeWindowPixel(
+	x, y int,
+	exact bool,
+	window WindowSelector,
+) error {
+	return d.rawWindow("resizewindowpixel", formatVec(x, y, exact), window)
+}
+
+// MoveWindowPixel moves window. When exact is false x and y are relative to
+// the current position.
+func (d Dispatcher) MoveWindowPixel(
+	x, y int,
+	exact bool,
+	window WindowSelector,
+) error {
+	return d.rawWindow("movewindowpixel", formatVec(x, y, exact), window)
+}
+
+// ToggleGroup toggles the active window into a group.
+func (d Dispatcher) ToggleGroup() error {
+	return d.Raw("togglegroup")
+}
+
+// rawWindow sends a dispatcher that takes an optional ",window" argument
+// after arg. The zero window is omitted.
+func (d Dispatcher) rawWindow(
+	dispatcher, arg string,
+	window WindowSelector,
+) error {
+	w, err := window.arg()
+	if err != nil {
+		return err
+	}
+	switch {
+	case w == "":
+		return d.Raw(dispatcher, arg)
+	case arg == "":
+		return d.Raw(dispatcher, w)
+	default:
+		return d.Raw(dispatcher, arg+","+w)
+	}
+}
+
+func formatVec(x, y int, exact bool) string {
+	s := strconv.Itoa(x) + " " + strconv.Itoa(y)
+	if exact {
+		return "exact " + s
+	}
+	return s
+}
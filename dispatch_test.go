@@ -0,0 +1,87 @@
+package hyprland
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	var got string
+	d := Dispatcher{send: func(cmd string) error {
+		got = cmd
+		return nil
+	}}
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"KillActive", d.KillActive, "dispatch killactive"},
+		{
+			"FocusWindow",
+			func() error { return d.FocusWindow(WindowByAddress("1")) },
+			"dispatch focuswindow address:0x1",
+		},
+		{
+			"MoveToWorkspace",
+			func() error {
+				return d.MoveToWorkspace(WorkspaceByID(2), WindowSelector{})
+			},
+			"dispatch movetoworkspace 2",
+		},
+		{
+			"MoveToWorkspaceSilent",
+			func() error {
+				return d.MoveToWorkspaceSilent(
+					WorkspaceSpecial("scratch"),
+					WindowByPID(10),
+				)
+			},
+			"dispatch movetoworkspacesilent special:scratch,pid:10",
+		},
+		{
+			"Fullscreen",
+			func() error { return d.Fullscreen(FullscreenMaximize) },
+			"dispatch fullscreen 1",
+		},
+		{
+			"ResizeActive",
+			func() error { return d.ResizeActive(10, -20, false) },
+			"dispatch resizeactive 10 -20",
+		},
+		{
+			"MoveWindowPixel",
+			func() error {
+				return d.MoveWindowPixel(5, 6, true, WindowByClass("kitty"))
+			},
+			"dispatch movewindowpixel exact 5 6,class:kitty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("Dispatcher."+tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s() failed: %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s() sent %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	if err := checkResponse("dispatch exec", []byte("ok")); err != nil {
+		t.Errorf("checkResponse(ok) = %v, want nil", err)
+	}
+
+	err := checkResponse("dispatch nope", []byte("Invalid dispatcher\n"))
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.Message != "Invalid dispatcher" {
+		t.Errorf("checkResponse() = %v, want Invalid dispatcher", err)
+	}
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("checkResponse() = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,62 @@
+package hyprland
+
+// ErrorPolicy decides what an EventListener does with an event it fails to
+// decode, e.g. a line without ">>" or arguments in an unexpected format.
+type ErrorPolicy int
+
+const (
+	// ErrorAbort stops listening and returns the DecodeError. This is the
+	// default.
+	ErrorAbort ErrorPolicy = iota
+	// ErrorSkip drops the event, reports the error to OnError and keeps
+	// listening. Events with malformed arguments still reach OnAllEvents,
+	// which is called before decoding. Lines without ">>" reach no handler.
+	ErrorSkip
+	// ErrorUnknown reports the error to OnError and delivers the event as
+	// UnknownEvent, to OnUnknown and the Unknown method of the handler. Lines
+	// without ">>" are delivered as an event without a name.
+	ErrorUnknown
+)
+
+// OnErrorFunc is called with an event that failed to decode and the
+// DecodeError describing why. Recover also reports panicking handlers to it
+// with a PanicError.
+type OnErrorFunc func(ctx *EventContext, err error)
+
+func (l *EventListener) errorPolicy() ErrorPolicy {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ErrorPolicy
+}
+
+// handleError applies the ErrorPolicy to a malformed event. It returns err
+// when listening should stop and nil otherwise.
+func (l *EventListener) handleError(ctx *EventContext, err error) error {
+	l.mu.Lock()
+	policy, onError := l.ErrorPolicy, l.onError
+	l.mu.Unlock()
+
+	if policy == ErrorAbort {
+		return err
+	}
+	if onError != nil {
+		onError(ctx, err)
+	}
+	return nil
+}
+
+// decode decodes the event of ctx according to the ErrorPolicy. It returns a
+// nil EventValue and error for skipped events.
+func (l *EventListener) decode(ctx *EventContext) (EventValue, error) {
+	v, err := decodeEvent(ctx)
+	if err == nil {
+		return v, nil
+	}
+	if err := l.handleError(ctx, err); err != nil {
+		return nil, err
+	}
+	if l.errorPolicy() == ErrorUnknown {
+		return UnknownEvent{Name: ctx.Event, Data: ctx.RawData}, nil
+	}
+	return nil, nil
+}
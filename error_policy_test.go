@@ -0,0 +1,113 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestErrorPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ErrorPolicy
+		want   []string
+	}{
+		{
+			"Skip",
+			ErrorSkip,
+			[]string{"all", "all", "error", "error", "openlayer"},
+		},
+		{
+			"Unknown",
+			ErrorUnknown,
+			[]string{
+				"all", "all", "all", "error", "error", "openlayer", "unknown",
+				"unknown",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := hyprlandtest.NewServer(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			got := make(chan string, 10)
+			l := NewEventListener()
+			l.ErrorPolicy = tt.policy
+			l.OnError(func(_ *EventContext, err error) {
+				if errors.Is(err, ErrDecode) {
+					got <- "error"
+				}
+			})
+			l.OnAllEvents(func(*EventContext) { got <- "all" })
+			l.OnUnknown(func(*EventContext) { got <- "unknown" })
+			l.OnOpenLayer(func(*EventContext, string) { got <- "openlayer" })
+			listen(ctx, t, l)
+			if err := s.WaitListeners(ctx, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			s.Emit("workspacev2", "web")
+			s.EmitRaw("garbage")
+			s.Emit("openlayer", "waybar")
+
+			// the order is pinned by TestAllEventsBeforeDecoding
+			lines := receive(ctx, t, got, len(tt.want))
+			slices.Sort(lines)
+			if !slices.Equal(lines, tt.want) {
+				t.Errorf("got %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorPolicyAbort(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errc := listen(ctx, t, NewEventListener())
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Emit("workspacev2", "web")
+
+	var decodeErr *DecodeError
+	if err := <-errc; !errors.As(err, &decodeErr) ||
+		decodeErr.Source != "workspacev2" {
+		t.Errorf("Listen() = %v, want DecodeError for workspacev2", err)
+	}
+}
+
+func TestAllEventsBeforeDecoding(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got := make(chan string, 10)
+	l := NewEventListener()
+	l.ErrorPolicy = ErrorSkip
+	l.OnError(func(*EventContext, error) { got <- "error" })
+	l.OnAllEvents(func(*EventContext) { got <- "all" })
+	l.OnOpenLayer(func(*EventContext, string) { got <- "openlayer" })
+	listen(ctx, t, l)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Emit("workspacev2", "web")
+	s.Emit("openlayer", "waybar")
+
+	// OnAllEvents runs before decoding, so it sees the malformed event before
+	// OnError reports it
+	want := []string{"all", "error", "all", "openlayer"}
+	if lines := receive(ctx, t, got, len(want)); !slices.Equal(lines, want) {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,73 @@
+package hyprland
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoInstance is returned when no hyprland instance can be found
+	ErrNoInstance = errors.New("no hyprland instance found")
+	// ErrSocketUnavailable is returned when a hyprland socket can not be
+	// dialed, e.g. hyprland is not running or the socket path is wrong
+	ErrSocketUnavailable = errors.New("hyprland socket unavailable")
+	// ErrUnknownRequest is returned when hyprland does not know the request
+	ErrUnknownRequest = errors.New("unknown request")
+	// ErrInvalidArgument is returned when hyprland rejects a request, e.g. an
+	// invalid dispatcher or a config option that does not exist
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrDecode is returned when a response or event can not be decoded
+	ErrDecode = errors.New("failed to decode")
+)
+
+// ResponseError is a textual error reply from hyprland. It matches
+// ErrUnknownRequest or ErrInvalidArgument with errors.Is.
+type ResponseError struct {
+	// Command is the request that was sent
+	Command string
+	// Message is the reply of hyprland
+	Message string
+}
+
+func (e *ResponseError) Error() string {
+	return e.Command + ": " + e.Message
+}
+
+// Is implements errors.Is
+func (e *ResponseError) Is(target error) bool {
+	if strings.EqualFold(e.Message, "unknown request") {
+		return target == ErrUnknownRequest
+	}
+	return target == ErrInvalidArgument
+}
+
+// DecodeError is returned when a response or event payload can not be
+// decoded. It matches ErrDecode with errors.Is.
+type DecodeError struct {
+	// Source is the request command or the event name
+	Source string
+	// Payload is the raw data that failed to decode
+	Payload []byte
+	// Err is the underlying error
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s %s: %v", ErrDecode, e.Source, e.Err)
+}
+
+// Unwrap implements errors.Unwrap
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is implements errors.Is
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// socketError wraps a dial error with ErrSocketUnavailable.
+func socketError(err error) error {
+	return fmt.Errorf("%w: %w", ErrSocketUnavailable, err)
+}
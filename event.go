@@ -2,11 +2,13 @@ package hyprland
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"net"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -35,7 +37,11 @@ func ParseEvent(raw string) (*EventContext, error) {
 	ctx.RawEvent = raw
 	event, data, found := strings.Cut(raw, EventSeparator)
 	if !found {
-		return ctx, fmt.Errorf("invalid event format: %q", raw)
+		return ctx, &DecodeError{
+			Source:  "event",
+			Payload: []byte(raw),
+			Err:     fmt.Errorf("invalid event format: %q", raw),
+		}
 	}
 	ctx.Event = Event(event)
 	ctx.RawData = data
@@ -49,15 +55,38 @@ var none = struct{}{}
 type EventListener struct {
 	// socket is the hyprland socket path
 	Socket SocketPath
-	// handler is the handler for all events
-	handler EventHandler
+	// Reconnect enables reconnecting when the connection drops, instead of
+	// returning from Listen. Set it before calling Listen. nil disables it.
+	Reconnect *ReconnectPolicy
+	// ErrorPolicy decides what happens to events that fail to decode. Set it
+	// before calling Listen.
+	ErrorPolicy ErrorPolicy
+	// pinned keeps Socket when reconnecting, see NewEventListenerFor
+	pinned bool
 	// conn is socket connection
 	conn net.Conn
-	// subscribed is a map of
+	// closed is closed by Close to stop the running Listen
+	closed chan struct{}
+	// subscribed is a map of events with an On* handler
 	subscribed map[Event]struct{}
 	// mu for sync safety
 	mu sync.Mutex
 
+	callbacks
+	// subscriptions are the handlers added by Subscribe, by event name
+	subscriptions map[Event][]subscription
+	// subscriptionsAll are the handlers added by SubscribeAll
+	subscriptionsAll []subscription
+	// nextID is the id of the next subscription
+	nextID uint64
+}
+
+// callbacks are the handlers set by SetHandler and the On* methods. They are
+// copied under mu before an event is dispatched.
+type callbacks struct {
+	// handler is the handler for all events
+	handler EventHandler
+
 	// on handlers
 	onAllEvents          OnAllEventsFunc
 	onWorkspace          OnWorkspaceFunc
@@ -103,9 +132,16 @@ type EventListener struct {
 	onMinimized          OnMinimizedFunc
 	onBell               OnBellFunc
 	onUnknown            OnUnknownFunc
+	onError              OnErrorFunc
+	onConnected          OnConnectedFunc
+	onDisconnected       OnDisconnectedFunc
+
+	// middleware wraps the handler calls, see Use
+	middleware []Middleware
 }
 
-// NewEventListener creates a new EventListener.
+// NewEventListener creates a new EventListener for the current instance. See
+// CurrentInstance.
 func NewEventListener() *EventListener {
 	l := new(EventListener)
 	l.Socket = should(GetEventSocket())
@@ -115,31 +151,57 @@ func NewEventListener() *EventListener {
 
 // HasHandler returns if given event as a handler.
 func (l *EventListener) HasHandler(event Event) bool {
-	if l.onAllEvents != nil && l.handler != nil {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.onAllEvents != nil || l.handler != nil {
+		return true
+	}
+	if len(l.subscriptionsAll) != 0 {
 		return true
 	}
 
-	if event.IsKnown() {
+	if event.IsKnown() || event == EventConnected ||
+		event == EventDisconnected {
 		_, ok := l.subscribed[event]
-		return ok
+		return ok || len(l.subscriptions[event]) != 0
 	}
 
-	return l.onUnknown != nil
+	return l.onUnknown != nil || len(l.subscriptions[unknownKey]) != 0
 }
 
 // IsConnected returns if the listener is connected to hyprland socket.
 func (l *EventListener) IsConnected() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	return l.conn != nil
 }
 
-// Close closes the underlying socket connection
+// Close closes the underlying socket connection. A running Listen returns
+// and does not reconnect, even with a ReconnectPolicy.
 func (l *EventListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed != nil && !l.isClosed() {
+		close(l.closed)
+	}
 	if l.conn != nil {
 		return l.conn.Close()
 	}
 	return nil
 }
 
+// isClosed returns if Close was called since Listen started. l.mu must be
+// held.
+func (l *EventListener) isClosed() bool {
+	select {
+	case <-l.closed:
+		return true
+	default:
+		return false
+	}
+}
+
 // SetHandler sets the event handler
 func (l *EventListener) SetHandler(handler EventHandler) {
 	l.mu.Lock()
@@ -166,7 +228,7 @@ func (l *EventListener) OnWorkspace(fn OnWorkspaceFunc) {
 func (l *EventListener) OnWorkspaceV2(fn OnWorkspaceV2Func) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.subscribed[EventWorkspace] = none
+	l.subscribed[EventWorkspaceV2] = none
 	l.onWorkspaceV2 = fn
 }
 
@@ -494,23 +556,101 @@ func (l *EventListener) OnBell(fn OnBellFunc) {
 func (l *EventListener) OnUnknown(fn OnUnknownFunc) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.subscribed[EventBell] = none
 	l.onUnknown = fn
 }
 
+// OnError sets the handler for malformed events. It is called unless the
+// ErrorPolicy is ErrorAbort.
+func (l *EventListener) OnError(fn OnErrorFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onError = fn
+}
+
 // Listen is a dials the socket2 connection and start listening for events
 // synchronously
 func (l *EventListener) Listen(ctx context.Context) error {
+	return l.listen(ctx, l.processEvent)
+}
+
+// Events dials the socket2 connection and returns an iterator over the decoded
+// events. The sequence ends when ctx is done or the loop is stopped. Errors are
+// yielded with a nil EventValue and end the sequence too.
+func (l *EventListener) Events(
+	ctx context.Context,
+) iter.Seq2[EventValue, error] {
+	return func(yield func(EventValue, error) bool) {
+		err := l.listen(ctx, func(ectx *EventContext) error {
+			v, err := l.decode(ectx)
+			if err != nil || v == nil {
+				return err
+			}
+			if !yield(v, nil) {
+				return errStopped
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopped) && ctx.Err() == nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Channel is like Events but delivers the events on a channel. The event
+// channel is closed when listening ends. The error channel receives the error
+// that ended it, if any, and is closed after the event channel.
+func (l *EventListener) Channel(
+	ctx context.Context,
+) (<-chan EventValue, <-chan error) {
+	events := make(chan EventValue)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(events)
+		for v, err := range l.Events(ctx) {
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case events <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, errc
+}
+
+// errStopped stops listen when the consumer of Events breaks the loop.
+var errStopped = errors.New("stopped")
+
+// listenOnce dials the socket2 connection and calls fn for every event until
+// ctx is done, the connection fails or fn returns an error. onConnect is called
+// once the connection is established.
+func (l *EventListener) listenOnce(
+	ctx context.Context,
+	fn func(*EventContext) error,
+	onConnect func(socket SocketPath) error,
+) error {
 	l.mu.Lock()
-	socket, err := GetEventSocket()
-	if err != nil {
-		return err
+	if l.Socket == "" {
+		socket, err := GetEventSocket()
+		if err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		l.Socket = socket
+	}
+	if l.isClosed() {
+		l.mu.Unlock()
+		return socketError(net.ErrClosed)
 	}
-	l.Socket = socket
 
 	conn, err := net.Dial("unix", string(l.Socket))
 	if err != nil {
-		return err
+		l.mu.Unlock()
+		return socketError(err)
 	}
 	l.conn = conn
 	defer func() {
@@ -518,38 +658,59 @@ func (l *EventListener) Listen(ctx context.Context) error {
 		l.conn = nil
 		l.mu.Unlock()
 	}()
-	defer conn.Close()
 
+	socket := l.Socket
 	l.mu.Unlock()
 
+	if err := onConnect(socket); err != nil {
+		conn.Close()
+		return err
+	}
+
 	events := make(chan string, 10)
 
 	scanner := bufio.NewScanner(conn)
 
+	// closing done and conn stops the scanner goroutine when returning
+	done := make(chan struct{})
 	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer func() {
+		close(done)
+		conn.Close()
+	}()
 	wg.Go(func() {
 		defer close(events)
 		for scanner.Scan() {
-			events <- scanner.Text()
+			select {
+			case events <- scanner.Text():
+			case <-done:
+				return
+			}
 		}
 	})
 
 	for {
 		select {
 		case <-ctx.Done():
-			conn.Close()
-			wg.Wait()
 			return ctx.Err()
 		case rawData, ok := <-events:
 			if !ok {
-				return errors.New("event channel closed expectedly")
+				return socketError(cmp.Or(scanner.Err(), io.EOF))
 			}
 			eventCtx, err := ParseEvent(rawData)
+			eventCtx.Context = ctx
 			if err != nil {
-				return err
+				if err := l.handleError(eventCtx, err); err != nil {
+					return err
+				}
+				if l.errorPolicy() != ErrorUnknown {
+					continue
+				}
+				// deliver the whole line as the data of an unnamed event
+				eventCtx.RawData = rawData
 			}
-			eventCtx.Context = ctx
-			if err := l.processEvent(eventCtx); err != nil {
+			if err := fn(eventCtx); err != nil {
 				return err
 			}
 		}
@@ -557,425 +718,525 @@ func (l *EventListener) Listen(ctx context.Context) error {
 }
 
 func (l *EventListener) processEvent(ctx *EventContext) error {
-	if l.onAllEvents != nil {
-		l.onAllEvents(ctx)
+	l.mu.Lock()
+	cb := l.callbacks
+	l.mu.Unlock()
+
+	if cb.onAllEvents != nil {
+		cb.call(ctx, func(ctx *EventContext) {
+			cb.onAllEvents(ctx)
+		})
 	}
-	if l.handler != nil {
-		l.handler.All(ctx)
+	if cb.handler != nil {
+		cb.call(ctx, func(ctx *EventContext) {
+			cb.handler.All(ctx)
+		})
 	}
-	switch ctx.Event {
-	case EventWorkspace:
-		if l.onWorkspace != nil {
-			l.onWorkspace(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.Workspace(ctx, ctx.RawData)
-		}
-	case EventWorkspaceV2:
-		id, name, err := cast2[int, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onWorkspaceV2 != nil {
-			l.onWorkspaceV2(ctx, id, name)
-		}
-		if l.handler != nil {
-			l.handler.WorkspaceV2(ctx, id, name)
-		}
-	case EventFocusedMonitor:
-		mon, name, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onFocusedMon != nil {
-			l.onFocusedMon(ctx, mon, name)
-		}
-		if l.handler != nil {
-			l.handler.FocusedMon(ctx, mon, name)
-		}
-	case EventFocusedMonitorV2:
-		mon, id, err := cast2[string, int](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onFocusedMonV2 != nil {
-			l.onFocusedMonV2(ctx, mon, id)
-		}
-		if l.handler != nil {
-			l.handler.FocusedMonV2(ctx, mon, id)
-		}
-	case EventActiveWindow:
-		class, title, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onActiveWindow != nil {
-			l.onActiveWindow(ctx, class, title)
-		}
-		if l.handler != nil {
-			l.handler.ActiveWindow(ctx, class, title)
-		}
-	case EventActiveWindowV2:
-		if l.onActiveWindowV2 != nil {
-			l.onActiveWindowV2(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.ActiveWindowV2(ctx, ctx.RawData)
-		}
-	case EventFullscreen:
-		fullscreen, err := cast[bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onFullscreen != nil {
-			l.onFullscreen(ctx, fullscreen)
-		}
-		if l.handler != nil {
-			l.handler.Fullscreen(ctx, fullscreen)
-		}
-	case EventMonitorRemoved:
-		if l.onMonitorRemoved != nil {
-			l.onMonitorRemoved(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.MonitorRemoved(ctx, ctx.RawData)
-		}
-	case EventMonitorRemovedV2:
-		id, name, desc, err := cast3[int, string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMonitorRemovedV2 != nil {
-			l.onMonitorRemovedV2(ctx, id, name, desc)
-		}
-		if l.handler != nil {
-			l.handler.MonitorRemovedV2(ctx, id, name, desc)
-		}
-	case EventMonitorAdded:
-		if l.onMonitorAdded != nil {
-			l.onMonitorAdded(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.MonitorAdded(ctx, ctx.RawData)
-		}
-	case EventMonitorAddedV2:
-		id, name, desc, err := cast3[int, string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMonitorAddedV2 != nil {
-			l.onMonitorAddedV2(ctx, id, name, desc)
-		}
-		if l.handler != nil {
-			l.handler.MonitorAddedV2(ctx, id, name, desc)
-		}
-	case EventCreateWorkspace:
-		if l.onCreateWorkspace != nil {
-			l.onCreateWorkspace(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.CreateWorkspace(ctx, ctx.RawData)
-		}
-	case EventCreateWorkspaceV2:
-		id, name, err := cast2[int, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onCreateWorkspaceV2 != nil {
-			l.onCreateWorkspaceV2(ctx, id, name)
-		}
-		if l.handler != nil {
-			l.handler.CreateWorkspaceV2(ctx, id, name)
-		}
-	case EventDestroyWorkspace:
-		if l.onDestroyWorkspace != nil {
-			l.onDestroyWorkspace(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.DestroyWorkspace(ctx, ctx.RawData)
-		}
-	case EventDestroyWorkspaceV2:
-		id, name, err := cast2[int, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onDestroyWorkspaceV2 != nil {
-			l.onDestroyWorkspaceV2(ctx, id, name)
-		}
-		if l.handler != nil {
-			l.handler.DestroyWorkspaceV2(ctx, id, name)
-		}
-	case EventMoveWorkspace:
-		name, mon, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMoveWorkspace != nil {
-			l.onMoveWorkspace(ctx, name, mon)
-		}
-		if l.handler != nil {
-			l.handler.MoveWorkspace(ctx, name, mon)
-		}
-	case EventMoveWorkspaceV2:
-		id, name, mon, err := cast3[int, string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMoveWorkspaceV2 != nil {
-			l.onMoveWorkspaceV2(ctx, id, name, mon)
-		}
-		if l.handler != nil {
-			l.handler.MoveWorkspaceV2(ctx, id, name, mon)
-		}
-	case EventRenameWorkspace:
-		id, name, err := cast2[int, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onRenameWorkspace != nil {
-			l.onRenameWorkspace(ctx, id, name)
-		}
-		if l.handler != nil {
-			l.handler.RenameWorkspace(ctx, id, name)
-		}
-	case EventActiveSpecial:
-		name, mon, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onActiveSpecial != nil {
-			l.onActiveSpecial(ctx, name, mon)
-		}
-		if l.handler != nil {
-			l.handler.ActiveSpecial(ctx, name, mon)
-		}
-	case EventActiveSpecialV2:
-		id, name, mon, err := cast3[int, string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onActiveSpecialV2 != nil {
-			l.onActiveSpecialV2(ctx, id, name, mon)
-		}
-		if l.handler != nil {
-			l.handler.ActiveSpecialV2(ctx, id, name, mon)
-		}
-	case EventActiveLayout:
-		kb, layout, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onActiveLayout != nil {
-			l.onActiveLayout(ctx, kb, layout)
-		}
-		if l.handler != nil {
-			l.handler.ActiveLayout(ctx, kb, layout)
-		}
-	case EventOpenWindow:
-		addr, workspace, class, title, err := cast4[string, string, string, string](
-			ctx.RawData,
-		)
-		if err != nil {
-			return err
-		}
-		if l.onOpenWindow != nil {
-			l.onOpenWindow(ctx, addr, workspace, class, title)
-		}
-		if l.handler != nil {
-			l.handler.OpenWindow(ctx, addr, workspace, class, title)
-		}
-	case EventCloseWindow:
-		if l.onCloseWindow != nil {
-			l.onCloseWindow(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.CloseWindow(ctx, ctx.RawData)
-		}
-	case EventMoveWindow:
-		addr, workspace, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMoveWindow != nil {
-			l.onMoveWindow(ctx, addr, workspace)
-		}
-		if l.handler != nil {
-			l.handler.MoveWindow(ctx, addr, workspace)
-		}
-	case EventMoveWindowV2:
-		addr, id, workspace, err := cast3[string, int, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMoveWindowV2 != nil {
-			l.onMoveWindowV2(ctx, addr, id, workspace)
-		}
-		if l.handler != nil {
-			l.handler.MoveWindowV2(ctx, addr, id, workspace)
-		}
-	case EventOpenLayer:
-		if l.onOpenLayer != nil {
-			l.onOpenLayer(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.OpenLayer(ctx, ctx.RawData)
-		}
-	case EventCloseLayer:
-		if l.onCloseLayer != nil {
-			l.onCloseLayer(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.CloseLayer(ctx, ctx.RawData)
-		}
-	case EventSubmap:
-		if l.onSubmap != nil {
-			l.onSubmap(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.Submap(ctx, ctx.RawData)
-		}
-	case EventChangeFloatingMode:
-		addr, floating, err := cast2[string, bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onChangeFloatingMode != nil {
-			l.onChangeFloatingMode(ctx, addr, floating)
-		}
-		if l.handler != nil {
-			l.handler.ChangeFloatingMode(ctx, addr, floating)
-		}
-	case EventUrgent:
-		if l.onUrgent != nil {
-			l.onUrgent(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.Urgent(ctx, ctx.RawData)
-		}
-	case EventScreencast:
-		state, owner, err := cast2[bool, bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onScreencast != nil {
-			l.onScreencast(ctx, state, owner)
-		}
-		if l.handler != nil {
-			l.handler.Screencast(ctx, state, owner)
-		}
-	case EventWindowTitle:
-		if l.onWindowTitle != nil {
-			l.onWindowTitle(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.WindowTitle(ctx, ctx.RawData)
-		}
-	case EventWindowTitleV2:
-		addr, title, err := cast2[string, string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onWindowTitleV2 != nil {
-			l.onWindowTitleV2(ctx, addr, title)
-		}
-		if l.handler != nil {
-			l.handler.WindowTitleV2(ctx, addr, title)
-		}
-	case EventToggleGroup:
-		parts := strings.Split(ctx.RawData, ",")
-		state, err := strconv.ParseBool(parts[0])
-		if err != nil {
-			return err
-		}
-		if l.onToggleGroup != nil {
-			l.onToggleGroup(ctx, state, parts[1:])
-		}
-		if l.handler != nil {
-			l.handler.ToggleGroup(ctx, state, parts[1:])
-		}
-	case EventMoveIntoGroup:
-		if l.onMoveIntoGroup != nil {
-			l.onMoveIntoGroup(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.MoveIntoGroup(ctx, ctx.RawData)
-		}
-	case EventMoveOutOfGroup:
-		if l.onMoveOutOfGroup != nil {
-			l.onMoveOutOfGroup(ctx, ctx.RawData)
-		}
-		if l.handler != nil {
-			l.handler.MoveOutOfGroup(ctx, ctx.RawData)
-		}
-	case EventIgnoreGroupLock:
-		state, err := cast[bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onIgnoreGroupLock != nil {
-			l.onIgnoreGroupLock(ctx, state)
-		}
-		if l.handler != nil {
-			l.handler.IgnoreGroupLock(ctx, state)
-		}
-	case EventLockGroups:
-		state, err := cast[bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onLockGroups != nil {
-			l.onLockGroups(ctx, state)
-		}
-		if l.handler != nil {
-			l.handler.LockGroups(ctx, state)
-		}
-	case EventConfigReloaded:
-		if l.onIgnoreGroupLock != nil {
-			l.onConfigReloaded(ctx)
-		}
-		if l.handler != nil {
-			l.handler.ConfigReloaded(ctx)
-		}
-	case EventPin:
-		addr, state, err := cast2[string, bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onPin != nil {
-			l.onPin(ctx, addr, state)
-		}
-		if l.handler != nil {
-			l.handler.Pin(ctx, addr, state)
-		}
-	case EventMinimized:
-		addr, state, err := cast2[string, bool](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onMinimized != nil {
-			l.onMinimized(ctx, addr, state)
-		}
-		if l.handler != nil {
-			l.handler.Minimized(ctx, addr, state)
-		}
-	case EventBell:
-		addr, err := cast[string](ctx.RawData)
-		if err != nil {
-			return err
-		}
-		if l.onBell != nil {
-			l.onBell(ctx, addr)
-		}
-		if l.handler != nil {
-			l.handler.Bell(ctx, addr)
-		}
-	default:
-		if l.onUnknown != nil {
-			l.onUnknown(ctx)
-		}
-		if l.handler != nil {
-			l.handler.Unknown(ctx)
+
+	// the ErrorPolicy applies to the typed handlers only
+	value, err := l.decode(ctx)
+	if err != nil || value == nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case WorkspaceEvent:
+		if cb.onWorkspace != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onWorkspace(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Workspace(ctx, v.Name)
+			})
+		}
+	case WorkspaceV2Event:
+		if cb.onWorkspaceV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onWorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.WorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+	case FocusedMonEvent:
+		if cb.onFocusedMon != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onFocusedMon(ctx, v.Monitor, v.Workspace)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.FocusedMon(ctx, v.Monitor, v.Workspace)
+			})
+		}
+	case FocusedMonV2Event:
+		if cb.onFocusedMonV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onFocusedMonV2(ctx, v.Monitor, v.WorkspaceID)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.FocusedMonV2(ctx, v.Monitor, v.WorkspaceID)
+			})
+		}
+	case ActiveWindowEvent:
+		if cb.onActiveWindow != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onActiveWindow(ctx, v.Class, v.Title)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ActiveWindow(ctx, v.Class, v.Title)
+			})
+		}
+	case ActiveWindowV2Event:
+		if cb.onActiveWindowV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onActiveWindowV2(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ActiveWindowV2(ctx, v.Address)
+			})
+		}
+	case FullscreenEvent:
+		if cb.onFullscreen != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onFullscreen(ctx, v.Fullscreen)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Fullscreen(ctx, v.Fullscreen)
+			})
+		}
+	case MonitorRemovedEvent:
+		if cb.onMonitorRemoved != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMonitorRemoved(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MonitorRemoved(ctx, v.Name)
+			})
+		}
+	case MonitorRemovedV2Event:
+		if cb.onMonitorRemovedV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMonitorRemovedV2(ctx, v.ID, v.Name, v.Description)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MonitorRemovedV2(ctx, v.ID, v.Name, v.Description)
+			})
+		}
+	case MonitorAddedEvent:
+		if cb.onMonitorAdded != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMonitorAdded(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MonitorAdded(ctx, v.Name)
+			})
+		}
+	case MonitorAddedV2Event:
+		if cb.onMonitorAddedV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMonitorAddedV2(ctx, v.ID, v.Name, v.Description)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MonitorAddedV2(ctx, v.ID, v.Name, v.Description)
+			})
+		}
+	case CreateWorkspaceEvent:
+		if cb.onCreateWorkspace != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onCreateWorkspace(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.CreateWorkspace(ctx, v.Name)
+			})
+		}
+	case CreateWorkspaceV2Event:
+		if cb.onCreateWorkspaceV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onCreateWorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.CreateWorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+	case DestroyWorkspaceEvent:
+		if cb.onDestroyWorkspace != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onDestroyWorkspace(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.DestroyWorkspace(ctx, v.Name)
+			})
+		}
+	case DestroyWorkspaceV2Event:
+		if cb.onDestroyWorkspaceV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onDestroyWorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.DestroyWorkspaceV2(ctx, v.ID, v.Name)
+			})
+		}
+	case MoveWorkspaceEvent:
+		if cb.onMoveWorkspace != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveWorkspace(ctx, v.Name, v.Monitor)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveWorkspace(ctx, v.Name, v.Monitor)
+			})
+		}
+	case MoveWorkspaceV2Event:
+		if cb.onMoveWorkspaceV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveWorkspaceV2(ctx, v.ID, v.Name, v.Monitor)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveWorkspaceV2(ctx, v.ID, v.Name, v.Monitor)
+			})
+		}
+	case RenameWorkspaceEvent:
+		if cb.onRenameWorkspace != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onRenameWorkspace(ctx, v.ID, v.NewName)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.RenameWorkspace(ctx, v.ID, v.NewName)
+			})
+		}
+	case ActiveSpecialEvent:
+		if cb.onActiveSpecial != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onActiveSpecial(ctx, v.Name, v.Monitor)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ActiveSpecial(ctx, v.Name, v.Monitor)
+			})
+		}
+	case ActiveSpecialV2Event:
+		if cb.onActiveSpecialV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onActiveSpecialV2(ctx, v.ID, v.Name, v.Monitor)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ActiveSpecialV2(ctx, v.ID, v.Name, v.Monitor)
+			})
+		}
+	case ActiveLayoutEvent:
+		if cb.onActiveLayout != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onActiveLayout(ctx, v.Keyboard, v.Layout)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ActiveLayout(ctx, v.Keyboard, v.Layout)
+			})
+		}
+	case OpenWindowEvent:
+		if cb.onOpenWindow != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onOpenWindow(ctx, v.Address, v.Workspace, v.Class, v.Title)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.OpenWindow(ctx, v.Address, v.Workspace, v.Class, v.Title)
+			})
+		}
+	case CloseWindowEvent:
+		if cb.onCloseWindow != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onCloseWindow(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.CloseWindow(ctx, v.Address)
+			})
+		}
+	case MoveWindowEvent:
+		if cb.onMoveWindow != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveWindow(ctx, v.Address, v.Workspace)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveWindow(ctx, v.Address, v.Workspace)
+			})
+		}
+	case MoveWindowV2Event:
+		if cb.onMoveWindowV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveWindowV2(ctx, v.Address, v.WorkspaceID, v.Workspace)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveWindowV2(ctx, v.Address, v.WorkspaceID, v.Workspace)
+			})
+		}
+	case OpenLayerEvent:
+		if cb.onOpenLayer != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onOpenLayer(ctx, v.Namespace)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.OpenLayer(ctx, v.Namespace)
+			})
+		}
+	case CloseLayerEvent:
+		if cb.onCloseLayer != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onCloseLayer(ctx, v.Namespace)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.CloseLayer(ctx, v.Namespace)
+			})
+		}
+	case SubmapEvent:
+		if cb.onSubmap != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onSubmap(ctx, v.Name)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Submap(ctx, v.Name)
+			})
+		}
+	case ChangeFloatingModeEvent:
+		if cb.onChangeFloatingMode != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onChangeFloatingMode(ctx, v.Address, v.Floating)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ChangeFloatingMode(ctx, v.Address, v.Floating)
+			})
+		}
+	case UrgentEvent:
+		if cb.onUrgent != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onUrgent(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Urgent(ctx, v.Address)
+			})
+		}
+	case ScreencastEvent:
+		if cb.onScreencast != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onScreencast(ctx, v.State, v.Owner)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Screencast(ctx, v.State, v.Owner)
+			})
+		}
+	case WindowTitleEvent:
+		if cb.onWindowTitle != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onWindowTitle(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.WindowTitle(ctx, v.Address)
+			})
+		}
+	case WindowTitleV2Event:
+		if cb.onWindowTitleV2 != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onWindowTitleV2(ctx, v.Address, v.Title)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.WindowTitleV2(ctx, v.Address, v.Title)
+			})
+		}
+	case ToggleGroupEvent:
+		if cb.onToggleGroup != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onToggleGroup(ctx, v.State, v.Addresses)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ToggleGroup(ctx, v.State, v.Addresses)
+			})
+		}
+	case MoveIntoGroupEvent:
+		if cb.onMoveIntoGroup != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveIntoGroup(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveIntoGroup(ctx, v.Address)
+			})
+		}
+	case MoveOutOfGroupEvent:
+		if cb.onMoveOutOfGroup != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMoveOutOfGroup(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.MoveOutOfGroup(ctx, v.Address)
+			})
+		}
+	case IgnoreGroupLockEvent:
+		if cb.onIgnoreGroupLock != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onIgnoreGroupLock(ctx, v.State)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.IgnoreGroupLock(ctx, v.State)
+			})
+		}
+	case LockGroupsEvent:
+		if cb.onLockGroups != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onLockGroups(ctx, v.State)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.LockGroups(ctx, v.State)
+			})
+		}
+	case ConfigReloadedEvent:
+		if cb.onConfigReloaded != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onConfigReloaded(ctx)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.ConfigReloaded(ctx)
+			})
+		}
+	case PinEvent:
+		if cb.onPin != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onPin(ctx, v.Address, v.Pinned)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Pin(ctx, v.Address, v.Pinned)
+			})
+		}
+	case MinimizedEvent:
+		if cb.onMinimized != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onMinimized(ctx, v.Address, v.Minimized)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Minimized(ctx, v.Address, v.Minimized)
+			})
+		}
+	case BellEvent:
+		if cb.onBell != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onBell(ctx, v.Address)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Bell(ctx, v.Address)
+			})
+		}
+	case UnknownEvent:
+		if cb.onUnknown != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onUnknown(ctx)
+			})
+		}
+		if cb.handler != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.handler.Unknown(ctx)
+			})
+		}
+	case ConnectedEvent:
+		if cb.onConnected != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onConnected(ctx, v.Socket)
+			})
+		}
+		if h, ok := cb.handler.(ConnectionHandler); ok {
+			cb.call(ctx, func(ctx *EventContext) {
+				h.Connected(ctx, v.Socket)
+			})
+		}
+	case DisconnectedEvent:
+		if cb.onDisconnected != nil {
+			cb.call(ctx, func(ctx *EventContext) {
+				cb.onDisconnected(ctx, v.Reason)
+			})
+		}
+		if h, ok := cb.handler.(ConnectionHandler); ok {
+			cb.call(ctx, func(ctx *EventContext) {
+				h.Disconnected(ctx, v.Reason)
+			})
 		}
 	}
+
+	l.dispatchSubscriptions(ctx, value, cb.call)
 	return nil
 }
@@ -0,0 +1,192 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want EventValue
+	}{
+		{"workspacev2>>2,web", WorkspaceV2Event{ID: 2, Name: "web"}},
+		{"activewindowv2>>5a1f", ActiveWindowV2Event{Address: "5a1f"}},
+		{
+			"movewindowv2>>5a1f,3,3",
+			MoveWindowV2Event{Address: "5a1f", WorkspaceID: 3, Workspace: "3"},
+		},
+		{
+			"activespecialv2>>,,DP-1",
+			ActiveSpecialV2Event{Monitor: "DP-1"},
+		},
+		{
+			"togglegroup>>0,5a1f,5a2f",
+			ToggleGroupEvent{Addresses: []string{"5a1f", "5a2f"}},
+		},
+		{
+			"openwindow>>5a1f,1,firefox,a, b,c",
+			OpenWindowEvent{
+				Address:   "5a1f",
+				Workspace: "1",
+				Class:     "firefox",
+				Title:     "a, b,c",
+			},
+		},
+		{
+			"moveworkspacev2>>4,a,b,DP-1",
+			MoveWorkspaceV2Event{ID: 4, Name: "a,b", Monitor: "DP-1"},
+		},
+		{
+			"activespecial>>special:a,b,DP-1",
+			ActiveSpecialEvent{Name: "special:a,b", Monitor: "DP-1"},
+		},
+		{"fullscreen>>1", FullscreenEvent{Fullscreen: true}},
+		{"configreloaded>>", ConfigReloadedEvent{}},
+		{"myplugin>>a,b", UnknownEvent{Name: "myplugin", Data: "a,b"}},
+	}
+
+	for _, tt := range tests {
+		ctx, err := ParseEvent(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeEvent(ctx)
+		if err != nil {
+			t.Errorf("decodeEvent(%q) failed: %v", tt.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decodeEvent(%q) = %#v, want %#v", tt.raw, got, tt.want)
+		}
+		if got.EventName() != ctx.Event {
+			t.Errorf("%T.EventName() = %q, want %q", got, got.EventName(), ctx.Event)
+		}
+	}
+
+	ctx, _ := ParseEvent("workspacev2>>web")
+	if _, err := decodeEvent(ctx); !errors.Is(err, ErrDecode) {
+		t.Errorf("decodeEvent(malformed) = %v, want ErrDecode", err)
+	}
+}
+
+func FuzzDecodeEvent(f *testing.F) {
+	f.Add("openwindow>>5a1f,1,kitty,~")
+	f.Add("activespecialv2>>,,DP-1")
+	f.Add("togglegroup>>1,5a1f")
+	f.Add("workspacev2>>,")
+	f.Fuzz(func(_ *testing.T, raw string) {
+		ctx, err := ParseEvent(raw)
+		if err != nil {
+			return
+		}
+		_, _ = decodeEvent(ctx)
+	})
+}
+
+func FuzzOpenWindowTitle(f *testing.F) {
+	f.Add("")
+	f.Add("a, b")
+	f.Add(",,,")
+	f.Add("~/src/go-hyprland, nvim")
+	f.Fuzz(func(t *testing.T, title string) {
+		ctx, err := ParseEvent("openwindow>>5a1f,1,kitty," + title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeEvent(ctx)
+		if err != nil {
+			t.Fatalf("decodeEvent(%q) failed: %v", ctx.RawData, err)
+		}
+		want := OpenWindowEvent{
+			Address:   "5a1f",
+			Workspace: "1",
+			Class:     "kitty",
+			Title:     title,
+		}
+		if got != want {
+			t.Errorf("decodeEvent(%q) = %#v, want %#v", ctx.RawData, got, want)
+		}
+	})
+}
+
+func FuzzMoveWorkspaceV2Name(f *testing.F) {
+	f.Add("web")
+	f.Add("a,b")
+	f.Add(",")
+	f.Fuzz(func(t *testing.T, name string) {
+		ctx, err := ParseEvent("moveworkspacev2>>4," + name + ",DP-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeEvent(ctx)
+		if err != nil {
+			t.Fatalf("decodeEvent(%q) failed: %v", ctx.RawData, err)
+		}
+		want := MoveWorkspaceV2Event{ID: 4, Name: name, Monitor: "DP-1"}
+		if got != want {
+			t.Errorf("decodeEvent(%q) = %#v, want %#v", ctx.RawData, got, want)
+		}
+	})
+}
+
+func TestEvents(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		if err := s.WaitListeners(ctx, 1); err != nil {
+			return
+		}
+		s.Emit("openlayer", "waybar")
+		s.Emit("workspacev2", "2,web")
+		s.Emit("submap", "resize")
+	}()
+
+	var got []EventValue
+	for ev, err := range NewEventListener().Events(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ev)
+		if _, ok := ev.(WorkspaceV2Event); ok {
+			break
+		}
+	}
+	want := []EventValue{
+		OpenLayerEvent{Namespace: "waybar"},
+		WorkspaceV2Event{ID: 2, Name: "web"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Events() = %#v, want %#v", got, want)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, errc := NewEventListener().Channel(ctx)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Emit("closewindow", "5a1f")
+	s.Emit("workspacev2", "nope")
+
+	if ev := <-events; ev != (CloseWindowEvent{Address: "5a1f"}) {
+		t.Errorf("Channel() = %#v, want CloseWindowEvent", ev)
+	}
+	if _, ok := <-events; ok {
+		t.Error("event channel should be closed after an error")
+	}
+	if err := <-errc; !errors.Is(err, ErrDecode) {
+		t.Errorf("Channel() error = %v, want ErrDecode", err)
+	}
+}
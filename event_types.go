@@ -212,7 +212,10 @@ var allEvents = map[Event]struct{}{
 }
 
 type (
-	// OnAllEventsFunc is called on every event emitted by Hyprland.
+	// OnAllEventsFunc is called on every event emitted by Hyprland, before
+	// its arguments are decoded. Events with malformed arguments reach it
+	// regardless of the ErrorPolicy, lines without ">>" only with
+	// ErrorUnknown.
 	OnAllEventsFunc func(ctx *EventContext)
 	// OnWorkspaceFunc is called when a user requests a workspace change. name
 	// is the name of the workspace being switched to.
@@ -365,7 +368,9 @@ type (
 // EventHandler is the interface for handling all Hyprland events. Each method
 // corresponds to a specific event type emitted by the Hyprland compositor.
 type EventHandler interface {
-	// All is called for every event emitted by Hyprland.
+	// All is called for every event emitted by Hyprland, before its arguments
+	// are decoded. Events with malformed arguments reach it regardless of the
+	// ErrorPolicy, lines without ">>" only with ErrorUnknown.
 	All(ctx *EventContext)
 	// Workspace is called when a user requests a workspace change. name is the
 	// name of the workspace being switched to.
@@ -0,0 +1,667 @@
+package hyprland
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EventValue is a decoded hyprland event. The concrete types are structs named
+// after the event, like OpenWindowEvent; use a type switch to tell them apart:
+//
+//	for ev, err := range l.Events(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		switch ev := ev.(type) {
+//		case hyprland.OpenWindowEvent:
+//			fmt.Println("opened", ev.Class)
+//		}
+//	}
+//
+// Window addresses in events have no 0x prefix.
+type EventValue interface {
+	// EventName returns the name of the event on the socket.
+	EventName() Event
+}
+
+// WorkspaceEvent is emitted on workspace change.
+type WorkspaceEvent struct {
+	Name string
+}
+
+// EventName implements EventValue.
+func (WorkspaceEvent) EventName() Event {
+	return EventWorkspace
+}
+
+// WorkspaceV2Event is emitted on workspace change (v2).
+type WorkspaceV2Event struct {
+	ID   int
+	Name string
+}
+
+// EventName implements EventValue.
+func (WorkspaceV2Event) EventName() Event {
+	return EventWorkspaceV2
+}
+
+// FocusedMonEvent is emitted when the active monitor changes.
+type FocusedMonEvent struct {
+	Monitor   string
+	Workspace string
+}
+
+// EventName implements EventValue.
+func (FocusedMonEvent) EventName() Event {
+	return EventFocusedMonitor
+}
+
+// FocusedMonV2Event is emitted when the active monitor changes (v2).
+type FocusedMonV2Event struct {
+	Monitor     string
+	WorkspaceID int
+}
+
+// EventName implements EventValue.
+func (FocusedMonV2Event) EventName() Event {
+	return EventFocusedMonitorV2
+}
+
+// ActiveWindowEvent is emitted when the active window changes.
+type ActiveWindowEvent struct {
+	Class string
+	Title string
+}
+
+// EventName implements EventValue.
+func (ActiveWindowEvent) EventName() Event {
+	return EventActiveWindow
+}
+
+// ActiveWindowV2Event is emitted when the active window changes (v2).
+type ActiveWindowV2Event struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (ActiveWindowV2Event) EventName() Event {
+	return EventActiveWindowV2
+}
+
+// FullscreenEvent is emitted when a window's fullscreen status changes.
+type FullscreenEvent struct {
+	Fullscreen bool
+}
+
+// EventName implements EventValue.
+func (FullscreenEvent) EventName() Event {
+	return EventFullscreen
+}
+
+// MonitorRemovedEvent is emitted when a monitor is disconnected.
+type MonitorRemovedEvent struct {
+	Name string
+}
+
+// EventName implements EventValue.
+func (MonitorRemovedEvent) EventName() Event {
+	return EventMonitorRemoved
+}
+
+// MonitorRemovedV2Event is emitted when a monitor is disconnected (v2).
+type MonitorRemovedV2Event struct {
+	ID          int
+	Name        string
+	Description string
+}
+
+// EventName implements EventValue.
+func (MonitorRemovedV2Event) EventName() Event {
+	return EventMonitorRemovedV2
+}
+
+// MonitorAddedEvent is emitted when a monitor is connected.
+type MonitorAddedEvent struct {
+	Name string
+}
+
+// EventName implements EventValue.
+func (MonitorAddedEvent) EventName() Event {
+	return EventMonitorAdded
+}
+
+// MonitorAddedV2Event is emitted when a monitor is connected (v2).
+type MonitorAddedV2Event struct {
+	ID          int
+	Name        string
+	Description string
+}
+
+// EventName implements EventValue.
+func (MonitorAddedV2Event) EventName() Event {
+	return EventMonitorAddedV2
+}
+
+// CreateWorkspaceEvent is emitted when a workspace is created.
+type CreateWorkspaceEvent struct {
+	Name string
+}
+
+// EventName implements EventValue.
+func (CreateWorkspaceEvent) EventName() Event {
+	return EventCreateWorkspace
+}
+
+// CreateWorkspaceV2Event is emitted when a workspace is created (v2).
+type CreateWorkspaceV2Event struct {
+	ID   int
+	Name string
+}
+
+// EventName implements EventValue.
+func (CreateWorkspaceV2Event) EventName() Event {
+	return EventCreateWorkspaceV2
+}
+
+// DestroyWorkspaceEvent is emitted when a workspace is destroyed.
+type DestroyWorkspaceEvent struct {
+	Name string
+}
+
+// EventName implements EventValue.
+func (DestroyWorkspaceEvent) EventName() Event {
+	return EventDestroyWorkspace
+}
+
+// DestroyWorkspaceV2Event is emitted when a workspace is destroyed (v2).
+type DestroyWorkspaceV2Event struct {
+	ID   int
+	Name string
+}
+
+// EventName implements EventValue.
+func (DestroyWorkspaceV2Event) EventName() Event {
+	return EventDestroyWorkspaceV2
+}
+
+// MoveWorkspaceEvent is emitted when a workspace moves to a different monitor.
+type MoveWorkspaceEvent struct {
+	Name    string
+	Monitor string
+}
+
+// EventName implements EventValue.
+func (MoveWorkspaceEvent) EventName() Event {
+	return EventMoveWorkspace
+}
+
+// MoveWorkspaceV2Event is emitted when a workspace moves to a different monitor
+// (v2).
+type MoveWorkspaceV2Event struct {
+	ID      int
+	Name    string
+	Monitor string
+}
+
+// EventName implements EventValue.
+func (MoveWorkspaceV2Event) EventName() Event {
+	return EventMoveWorkspaceV2
+}
+
+// RenameWorkspaceEvent is emitted when a workspace is renamed.
+type RenameWorkspaceEvent struct {
+	ID      int
+	NewName string
+}
+
+// EventName implements EventValue.
+func (RenameWorkspaceEvent) EventName() Event {
+	return EventRenameWorkspace
+}
+
+// ActiveSpecialEvent is emitted when the special workspace on a monitor
+// changes.
+type ActiveSpecialEvent struct {
+	// Name is empty when the special workspace is closed
+	Name    string
+	Monitor string
+}
+
+// EventName implements EventValue.
+func (ActiveSpecialEvent) EventName() Event {
+	return EventActiveSpecial
+}
+
+// ActiveSpecialV2Event is emitted when the special workspace on a monitor
+// changes (v2).
+type ActiveSpecialV2Event struct {
+	// ID and Name are empty when the special workspace is closed
+	ID      int
+	Name    string
+	Monitor string
+}
+
+// EventName implements EventValue.
+func (ActiveSpecialV2Event) EventName() Event {
+	return EventActiveSpecialV2
+}
+
+// ActiveLayoutEvent is emitted when the layout of the active keyboard changes.
+type ActiveLayoutEvent struct {
+	Keyboard string
+	Layout   string
+}
+
+// EventName implements EventValue.
+func (ActiveLayoutEvent) EventName() Event {
+	return EventActiveLayout
+}
+
+// OpenWindowEvent is emitted when a window is opened.
+type OpenWindowEvent struct {
+	Address   string
+	Workspace string
+	Class     string
+	Title     string
+}
+
+// EventName implements EventValue.
+func (OpenWindowEvent) EventName() Event {
+	return EventOpenWindow
+}
+
+// CloseWindowEvent is emitted when a window is closed.
+type CloseWindowEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (CloseWindowEvent) EventName() Event {
+	return EventCloseWindow
+}
+
+// MoveWindowEvent is emitted when a window moves to a different workspace.
+type MoveWindowEvent struct {
+	Address   string
+	Workspace string
+}
+
+// EventName implements EventValue.
+func (MoveWindowEvent) EventName() Event {
+	return EventMoveWindow
+}
+
+// MoveWindowV2Event is emitted when a window moves to a different workspace
+// (v2).
+type MoveWindowV2Event struct {
+	Address     string
+	WorkspaceID int
+	Workspace   string
+}
+
+// EventName implements EventValue.
+func (MoveWindowV2Event) EventName() Event {
+	return EventMoveWindowV2
+}
+
+// OpenLayerEvent is emitted when a layer surface is mapped.
+type OpenLayerEvent struct {
+	Namespace string
+}
+
+// EventName implements EventValue.
+func (OpenLayerEvent) EventName() Event {
+	return EventOpenLayer
+}
+
+// CloseLayerEvent is emitted when a layer surface is unmapped.
+type CloseLayerEvent struct {
+	Namespace string
+}
+
+// EventName implements EventValue.
+func (CloseLayerEvent) EventName() Event {
+	return EventCloseLayer
+}
+
+// SubmapEvent is emitted when a keybind submap changes.
+type SubmapEvent struct {
+	// Name is empty for the default submap
+	Name string
+}
+
+// EventName implements EventValue.
+func (SubmapEvent) EventName() Event {
+	return EventSubmap
+}
+
+// ChangeFloatingModeEvent is emitted when a window toggles its floating mode.
+type ChangeFloatingModeEvent struct {
+	Address  string
+	Floating bool
+}
+
+// EventName implements EventValue.
+func (ChangeFloatingModeEvent) EventName() Event {
+	return EventChangeFloatingMode
+}
+
+// UrgentEvent is emitted when a window requests an urgent state.
+type UrgentEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (UrgentEvent) EventName() Event {
+	return EventUrgent
+}
+
+// ScreencastEvent is emitted when a client's screencopy state changes.
+type ScreencastEvent struct {
+	// State is true when screencopy starts
+	State bool
+	// Owner is true for window share, false for monitor share
+	Owner bool
+}
+
+// EventName implements EventValue.
+func (ScreencastEvent) EventName() Event {
+	return EventScreencast
+}
+
+// WindowTitleEvent is emitted when a window title changes.
+type WindowTitleEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (WindowTitleEvent) EventName() Event {
+	return EventWindowTitle
+}
+
+// WindowTitleV2Event is emitted when a window title changes (v2).
+type WindowTitleV2Event struct {
+	Address string
+	Title   string
+}
+
+// EventName implements EventValue.
+func (WindowTitleV2Event) EventName() Event {
+	return EventWindowTitleV2
+}
+
+// ToggleGroupEvent is emitted when the togglegroup command is used.
+type ToggleGroupEvent struct {
+	// State is true when the group was created, false when destroyed
+	State     bool
+	Addresses []string
+}
+
+// EventName implements EventValue.
+func (ToggleGroupEvent) EventName() Event {
+	return EventToggleGroup
+}
+
+// MoveIntoGroupEvent is emitted when a window is merged into a group.
+type MoveIntoGroupEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (MoveIntoGroupEvent) EventName() Event {
+	return EventMoveIntoGroup
+}
+
+// MoveOutOfGroupEvent is emitted when a window is removed from a group.
+type MoveOutOfGroupEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (MoveOutOfGroupEvent) EventName() Event {
+	return EventMoveOutOfGroup
+}
+
+// IgnoreGroupLockEvent is emitted when the ignoregrouplock setting is toggled.
+type IgnoreGroupLockEvent struct {
+	State bool
+}
+
+// EventName implements EventValue.
+func (IgnoreGroupLockEvent) EventName() Event {
+	return EventIgnoreGroupLock
+}
+
+// LockGroupsEvent is emitted when lockgroups is toggled.
+type LockGroupsEvent struct {
+	State bool
+}
+
+// EventName implements EventValue.
+func (LockGroupsEvent) EventName() Event {
+	return EventLockGroups
+}
+
+// ConfigReloadedEvent is emitted when the config finishes reloading.
+type ConfigReloadedEvent struct{}
+
+// EventName implements EventValue.
+func (ConfigReloadedEvent) EventName() Event {
+	return EventConfigReloaded
+}
+
+// PinEvent is emitted when a window is pinned or unpinned.
+type PinEvent struct {
+	Address string
+	Pinned  bool
+}
+
+// EventName implements EventValue.
+func (PinEvent) EventName() Event {
+	return EventPin
+}
+
+// MinimizedEvent is emitted when an external taskbar-like app requests
+// minimizing a window.
+type MinimizedEvent struct {
+	Address   string
+	Minimized bool
+}
+
+// EventName implements EventValue.
+func (MinimizedEvent) EventName() Event {
+	return EventMinimized
+}
+
+// BellEvent is emitted when an app rings the system bell via
+// xdg-system-bell-v1.
+type BellEvent struct {
+	Address string
+}
+
+// EventName implements EventValue.
+func (BellEvent) EventName() Event {
+	return EventBell
+}
+
+// UnknownEvent is an event without a typed binding, emitted by plugins or by
+// a newer hyprland.
+type UnknownEvent struct {
+	Name Event
+	Data string
+}
+
+// EventName implements EventValue.
+func (e UnknownEvent) EventName() Event {
+	return e.Name
+}
+
+// decodeEvent decodes the data of ctx into its typed EventValue. Malformed
+// data is returned as DecodeError.
+func decodeEvent(ctx *EventContext) (EventValue, error) {
+	v, err := decodeEventData(ctx.Event, ctx.RawData)
+	if err != nil {
+		return nil, &DecodeError{
+			Source:  string(ctx.Event),
+			Payload: []byte(ctx.RawData),
+			Err:     err,
+		}
+	}
+	return v, nil
+}
+
+// decodeEventData decodes the arguments of an event. The last argument of the
+// cast functions is the index of the argument that is free text and may
+// contain commas, see splitArgs.
+func decodeEventData(event Event, data string) (EventValue, error) {
+	switch event {
+	case EventWorkspace:
+		return WorkspaceEvent{Name: data}, nil
+	case EventWorkspaceV2:
+		id, name, err := cast2[int, string](data, 1)
+		return WorkspaceV2Event{ID: id, Name: name}, err
+	case EventFocusedMonitor:
+		monitor, workspace, err := cast2[string, string](data, 1)
+		return FocusedMonEvent{Monitor: monitor, Workspace: workspace}, err
+	case EventFocusedMonitorV2:
+		monitor, workspaceID, err := cast2[string, int](data, 0)
+		return FocusedMonV2Event{Monitor: monitor, WorkspaceID: workspaceID}, err
+	case EventActiveWindow:
+		class, title, err := cast2[string, string](data, 1)
+		return ActiveWindowEvent{Class: class, Title: title}, err
+	case EventActiveWindowV2:
+		return ActiveWindowV2Event{Address: data}, nil
+	case EventFullscreen:
+		fullscreen, err := cast[bool](data)
+		return FullscreenEvent{Fullscreen: fullscreen}, err
+	case EventMonitorRemoved:
+		return MonitorRemovedEvent{Name: data}, nil
+	case EventMonitorRemovedV2:
+		id, name, description, err := cast3[int, string, string](data, 2)
+		return MonitorRemovedV2Event{
+			ID:          id,
+			Name:        name,
+			Description: description,
+		}, err
+	case EventMonitorAdded:
+		return MonitorAddedEvent{Name: data}, nil
+	case EventMonitorAddedV2:
+		id, name, description, err := cast3[int, string, string](data, 2)
+		return MonitorAddedV2Event{
+			ID:          id,
+			Name:        name,
+			Description: description,
+		}, err
+	case EventCreateWorkspace:
+		return CreateWorkspaceEvent{Name: data}, nil
+	case EventCreateWorkspaceV2:
+		id, name, err := cast2[int, string](data, 1)
+		return CreateWorkspaceV2Event{ID: id, Name: name}, err
+	case EventDestroyWorkspace:
+		return DestroyWorkspaceEvent{Name: data}, nil
+	case EventDestroyWorkspaceV2:
+		id, name, err := cast2[int, string](data, 1)
+		return DestroyWorkspaceV2Event{ID: id, Name: name}, err
+	case EventMoveWorkspace:
+		name, monitor, err := cast2[string, string](data, 0)
+		return MoveWorkspaceEvent{Name: name, Monitor: monitor}, err
+	case EventMoveWorkspaceV2:
+		id, name, monitor, err := cast3[int, string, string](data, 1)
+		return MoveWorkspaceV2Event{ID: id, Name: name, Monitor: monitor}, err
+	case EventRenameWorkspace:
+		id, newName, err := cast2[int, string](data, 1)
+		return RenameWorkspaceEvent{ID: id, NewName: newName}, err
+	case EventActiveSpecial:
+		name, monitor, err := cast2[string, string](data, 0)
+		return ActiveSpecialEvent{Name: name, Monitor: monitor}, err
+	case EventActiveSpecialV2:
+		rawID, name, monitor, err := cast3[string, string, string](data, 1)
+		if err != nil {
+			return nil, err
+		}
+		// hyprland sends an empty id when the special workspace is closed
+		id := 0
+		if rawID != "" {
+			if id, err = strconv.Atoi(rawID); err != nil {
+				return nil, err
+			}
+		}
+		return ActiveSpecialV2Event{ID: id, Name: name, Monitor: monitor}, nil
+	case EventActiveLayout:
+		keyboard, layout, err := cast2[string, string](data, 1)
+		return ActiveLayoutEvent{Keyboard: keyboard, Layout: layout}, err
+	case EventOpenWindow:
+		// the title is the free field, a workspace name with commas can not be
+		// told apart from it
+		address, workspace, class, title, err := cast4[
+			string, string, string, string,
+		](data, 3)
+		return OpenWindowEvent{
+			Address:   address,
+			Workspace: workspace,
+			Class:     class,
+			Title:     title,
+		}, err
+	case EventCloseWindow:
+		return CloseWindowEvent{Address: data}, nil
+	case EventMoveWindow:
+		address, workspace, err := cast2[string, string](data, 1)
+		return MoveWindowEvent{Address: address, Workspace: workspace}, err
+	case EventMoveWindowV2:
+		address, workspaceID, workspace, err := cast3[string, int, string](data, 2)
+		return MoveWindowV2Event{
+			Address:     address,
+			WorkspaceID: workspaceID,
+			Workspace:   workspace,
+		}, err
+	case EventOpenLayer:
+		return OpenLayerEvent{Namespace: data}, nil
+	case EventCloseLayer:
+		return CloseLayerEvent{Namespace: data}, nil
+	case EventSubmap:
+		return SubmapEvent{Name: data}, nil
+	case EventChangeFloatingMode:
+		address, floating, err := cast2[string, bool](data, 0)
+		return ChangeFloatingModeEvent{Address: address, Floating: floating}, err
+	case EventUrgent:
+		return UrgentEvent{Address: data}, nil
+	case EventScreencast:
+		state, owner, err := cast2[bool, bool](data, 1)
+		return ScreencastEvent{State: state, Owner: owner}, err
+	case EventWindowTitle:
+		return WindowTitleEvent{Address: data}, nil
+	case EventWindowTitleV2:
+		address, title, err := cast2[string, string](data, 1)
+		return WindowTitleV2Event{Address: address, Title: title}, err
+	case EventToggleGroup:
+		parts := strings.Split(data, ",")
+		state, err := strconv.ParseBool(parts[0])
+		return ToggleGroupEvent{State: state, Addresses: parts[1:]}, err
+	case EventMoveIntoGroup:
+		return MoveIntoGroupEvent{Address: data}, nil
+	case EventMoveOutOfGroup:
+		return MoveOutOfGroupEvent{Address: data}, nil
+	case EventIgnoreGroupLock:
+		state, err := cast[bool](data)
+		return IgnoreGroupLockEvent{State: state}, err
+	case EventLockGroups:
+		state, err := cast[bool](data)
+		return LockGroupsEvent{State: state}, err
+	case EventConfigReloaded:
+		return ConfigReloadedEvent{}, nil
+	case EventPin:
+		address, pinned, err := cast2[string, bool](data, 0)
+		return PinEvent{Address: address, Pinned: pinned}, err
+	case EventMinimized:
+		address, minimized, err := cast2[string, bool](data, 0)
+		return MinimizedEvent{Address: address, Minimized: minimized}, err
+	case EventBell:
+		return BellEvent{Address: data}, nil
+	case EventConnected:
+		return ConnectedEvent{Socket: SocketPath(data)}, nil
+	case EventDisconnected:
+		return DisconnectedEvent{Reason: data}, nil
+	default:
+		return UnknownEvent{Name: event, Data: data}, nil
+	}
+}
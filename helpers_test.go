@@ -0,0 +1,69 @@
+package hyprland
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+// startInstance starts a fake hyprland in its own runtime directory without
+// touching the environment. It is closed when the test ends.
+func startInstance(t testing.TB) *hyprlandtest.Server {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "hypr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	s, err := hyprlandtest.Start(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// listen runs l until the test ends. The returned channel receives the error
+// of Listen. The test waits for Listen to return before it ends.
+func listen(
+	ctx context.Context,
+	t testing.TB,
+	l *EventListener,
+) <-chan error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(ctx)
+	errc := make(chan error, 1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		errc <- l.Listen(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return errc
+}
+
+// receive returns the next n values from ch. The test fails when ctx is done
+// first.
+func receive[T any](
+	ctx context.Context,
+	t testing.TB,
+	ch <-chan T,
+	n int,
+) []T {
+	t.Helper()
+	values := make([]T, 0, n)
+	for range n {
+		select {
+		case v := <-ch:
+			values = append(values, v)
+		case <-ctx.Done():
+			t.Fatalf("got %v, want %d values", values, n)
+		}
+	}
+	return values
+}
@@ -0,0 +1,1156 @@
+package hyprlandtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// specialWorkspaceStart is the id of the first special workspace, the next
+// ones count up from here.
+const specialWorkspaceStart = -99
+
+// Compositor is an in-memory model of a hyprland session. It serves the state
+// queries of a Server, applies dispatchers to its model and emits the events
+// hyprland would emit for every change. It is safe for concurrent use.
+type Compositor struct {
+	server *Server
+
+	// emitMu guards emitted, emitted signals its changes
+	emitMu sync.Mutex
+	// emitted is the number of changes whose events were written
+	emitted     uint64
+	emittedCond *sync.Cond
+
+	mu         sync.Mutex
+	monitors   []*monitor
+	workspaces []*workspace
+	clients    []*client
+	// focusedMon is the id of the focused monitor
+	focusedMon int
+	// focused is the address of the active window
+	focused string
+	// history is the focus history, most recent address first
+	history  []string
+	nextAddr uint64
+	// nextMonitor is the id of the next monitor, ids are never reused
+	nextMonitor int
+	// lastWorkspace is the previously focused workspace for "previous"
+	lastWorkspace int
+	// pending are the events of the current change, emitted on unlock
+	pending []string
+	// changes is the number of changes with events, it orders their writes
+	changes uint64
+}
+
+type monitor struct {
+	ID            int
+	Name          string
+	Description   string
+	Width, Height int
+	X             int
+	Active        int
+	// Previous is the previously active workspace for previous_per_monitor
+	Previous int
+	// Special is the id of the open special workspace, 0 when closed
+	Special int
+}
+
+type workspace struct {
+	ID      int
+	Name    string
+	Monitor int
+}
+
+type client struct {
+	Address      string
+	Class        string
+	Title        string
+	InitialClass string
+	InitialTitle string
+	PID          int
+	Workspace    int
+	Floating     bool
+	Fullscreen   int
+	Pinned       bool
+	Grouped      []string
+}
+
+// Window describes a window opened with Compositor.OpenWindow.
+type Window struct {
+	Class string
+	Title string
+	PID   int
+	// Workspace is the workspace id to open on, 0 means the focused one
+	Workspace int
+	Floating  bool
+}
+
+// NewCompositor creates an empty Compositor serving s. Add a monitor with
+// AddMonitor before opening windows.
+func NewCompositor(s *Server) *Compositor {
+	c := &Compositor{server: s, nextAddr: 0x5a1f00000000}
+	c.emittedCond = sync.NewCond(&c.emitMu)
+
+	s.HandleFunc("j/monitors", c.jsonHandler(c.monitorsJSON))
+	s.HandleFunc("j/workspaces", c.jsonHandler(c.workspacesJSON))
+	s.HandleFunc("j/clients", c.jsonHandler(c.clientsJSON))
+	s.HandleFunc("j/activewindow", c.jsonHandler(c.activeWindowJSON))
+	s.HandleFunc("j/activeworkspace", c.jsonHandler(c.activeWorkspaceJSON))
+	s.HandleFunc("dispatch ", c.handleDispatch)
+	return c
+}
+
+// AddMonitor connects a monitor and creates a workspace on it. The first
+// monitor is focused.
+func (c *Compositor) AddMonitor(name string, width, height int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	m := &monitor{
+		ID:          c.nextMonitor,
+		Name:        name,
+		Description: "hyprlandtest " + name,
+		Width:       width,
+		Height:      height,
+	}
+	for _, other := range c.monitors {
+		m.X = max(m.X, other.X+other.Width)
+	}
+	c.nextMonitor++
+	c.monitors = append(c.monitors, m)
+	c.emit("monitoradded", m.Name)
+	c.emit("monitoraddedv2", strconv.Itoa(m.ID), m.Name, m.Description)
+
+	ws := c.createWorkspace(c.freeWorkspaceID(), "", m)
+	m.Active = ws.ID
+	if len(c.monitors) == 1 {
+		c.focusedMon = m.ID
+	}
+}
+
+// RemoveMonitor disconnects a monitor. Its workspaces move to the first
+// remaining monitor.
+func (c *Compositor) RemoveMonitor(name string) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	m := c.monitorByName(name)
+	if m == nil {
+		return fmt.Errorf("no monitor %s", name)
+	}
+	c.monitors = slices.DeleteFunc(c.monitors, func(o *monitor) bool {
+		return o == m
+	})
+	c.emit("monitorremoved", m.Name)
+	c.emit("monitorremovedv2", strconv.Itoa(m.ID), m.Name, m.Description)
+
+	if len(c.monitors) == 0 {
+		return nil
+	}
+	target := c.monitors[0]
+	for _, ws := range c.workspaces {
+		if ws.Monitor != m.ID {
+			continue
+		}
+		ws.Monitor = target.ID
+		c.emit("moveworkspace", ws.Name, target.Name)
+		c.emit("moveworkspacev2", strconv.Itoa(ws.ID), ws.Name, target.Name)
+	}
+	if c.focusedMon == m.ID {
+		c.focusMonitor(target)
+	}
+	return nil
+}
+
+// OpenWindow maps a new window and focuses it. It returns the window address
+// with the 0x prefix.
+func (c *Compositor) OpenWindow(w Window) (string, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	mon := c.focusedMonitor()
+	if mon == nil {
+		return "", errors.New("no monitor, call AddMonitor first")
+	}
+
+	wsID := mon.Active
+	if mon.Special != 0 {
+		wsID = mon.Special
+	}
+	if w.Workspace != 0 {
+		wsID = w.Workspace
+		if c.workspace(wsID) == nil {
+			c.createWorkspace(wsID, "", mon)
+		}
+	}
+	ws := c.workspace(wsID)
+
+	cl := &client{
+		Address:      fmt.Sprintf("0x%x", c.nextAddr),
+		Class:        w.Class,
+		Title:        w.Title,
+		InitialClass: w.Class,
+		InitialTitle: w.Title,
+		PID:          w.PID,
+		Workspace:    ws.ID,
+		Floating:     w.Floating,
+	}
+	c.nextAddr += 0x1000
+	c.clients = append(c.clients, cl)
+	c.emit("openwindow", short(cl.Address), ws.Name, cl.Class, cl.Title)
+
+	if c.visible(ws) {
+		c.focusWindow(cl)
+	}
+	return cl.Address, nil
+}
+
+// SetTitle changes the title of a window.
+func (c *Compositor) SetTitle(address, title string) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	cl := c.clientByAddress(address)
+	if cl == nil {
+		return fmt.Errorf("no window %s", address)
+	}
+	cl.Title = title
+	c.emit("windowtitle", short(cl.Address))
+	c.emit("windowtitlev2", short(cl.Address), title)
+	if c.activeWindow() == cl {
+		c.emit("activewindow", cl.Class, cl.Title)
+	}
+	return nil
+}
+
+// ActiveWindow returns the address of the focused window, or "" when no
+// window is focused.
+func (c *Compositor) ActiveWindow() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cl := c.activeWindow(); cl != nil {
+		return cl.Address
+	}
+	return ""
+}
+
+// ActiveWorkspace returns the id of the active workspace on the focused
+// monitor.
+func (c *Compositor) ActiveWorkspace() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m := c.focusedMonitor(); m != nil {
+		return m.Active
+	}
+	return 0
+}
+
+// emit queues an event. c.mu must be held.
+func (c *Compositor) emit(event string, args ...string) {
+	c.pending = append(c.pending, event+">>"+strings.Join(args, ","))
+}
+
+// unlock releases c.mu and emits the queued events. They are written after
+// releasing c.mu, so listeners may query the compositor while reading them.
+// The events of concurrent changes are written in the order of the changes
+// and never interleaved.
+func (c *Compositor) unlock() {
+	lines := c.pending
+	c.pending = nil
+	if len(lines) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	turn := c.changes
+	c.changes++
+	c.mu.Unlock()
+
+	c.emitMu.Lock()
+	defer c.emitMu.Unlock()
+	for c.emitted != turn {
+		c.emittedCond.Wait()
+	}
+	for _, line := range lines {
+		c.server.EmitRaw(line)
+	}
+	c.emitted++
+	c.emittedCond.Broadcast()
+}
+
+func (c *Compositor) handleDispatch(cmd string) string {
+	name, arg, _ := strings.Cut(strings.TrimPrefix(cmd, "dispatch "), " ")
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if err := c.dispatch(name, strings.TrimSpace(arg)); err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+// dispatch applies a dispatcher. c.mu must be held.
+func (c *Compositor) dispatch(name, arg string) error {
+	switch name {
+	case "workspace":
+		ws, err := c.resolveWorkspace(arg, true)
+		if err != nil {
+			return err
+		}
+		c.switchWorkspace(ws)
+		return nil
+	case "movetoworkspace", "movetoworkspacesilent":
+		wsArg, winArg, _ := strings.Cut(arg, ",")
+		cl, err := c.resolveWindow(winArg)
+		if err != nil {
+			return err
+		}
+		ws, err := c.resolveWorkspace(wsArg, true)
+		if err != nil {
+			return err
+		}
+		c.moveToWorkspace(cl, ws, name == "movetoworkspacesilent")
+		return nil
+	case "togglespecialworkspace":
+		c.toggleSpecial(arg)
+		return nil
+	case "focuswindow":
+		cl, err := c.resolveWindow(arg)
+		if err != nil {
+			return err
+		}
+		if ws := c.workspace(cl.Workspace); !c.visible(ws) {
+			c.switchWorkspace(ws)
+		}
+		c.focusWindow(cl)
+		return nil
+	case "focusmonitor":
+		m := c.monitorByName(arg)
+		if m == nil {
+			return fmt.Errorf("no monitor %s", arg)
+		}
+		c.focusMonitor(m)
+		return nil
+	case "killactive", "closewindow":
+		cl, err := c.resolveWindow(arg)
+		if err != nil {
+			return err
+		}
+		c.closeWindow(cl)
+		return nil
+	case "togglefloating", "setfloating", "settiled":
+		cl, err := c.resolveWindow(arg)
+		if err != nil {
+			return err
+		}
+		floating := !cl.Floating
+		if name != "togglefloating" {
+			floating = name == "setfloating"
+		}
+		if floating != cl.Floating {
+			cl.Floating = floating
+			c.emit("changefloatingmode", short(cl.Address), flag(floating))
+		}
+		return nil
+	case "fullscreen":
+		cl := c.activeWindow()
+		if cl == nil {
+			return errors.New("no active window")
+		}
+		mode, err := strconv.Atoi(cmp.Or(arg, "0"))
+		if err != nil {
+			return fmt.Errorf("invalid fullscreen mode %q", arg)
+		}
+		switch {
+		case cl.Fullscreen != 0:
+			cl.Fullscreen = 0
+		case mode == 0:
+			// clients report 1 for maximized and 2 for fullscreen
+			cl.Fullscreen = 2
+		default:
+			cl.Fullscreen = 1
+		}
+		c.emit("fullscreen", flag(cl.Fullscreen != 0))
+		return nil
+	case "pin":
+		cl, err := c.resolveWindow(arg)
+		if err != nil {
+			return err
+		}
+		if !cl.Floating {
+			return errors.New("window is not floating")
+		}
+		cl.Pinned = !cl.Pinned
+		c.emit("pin", short(cl.Address), flag(cl.Pinned))
+		return nil
+	case "togglegroup":
+		return c.toggleGroup()
+	case "moveintogroup":
+		return c.moveIntoGroup(arg)
+	case "moveoutofgroup":
+		cl, err := c.resolveWindow(arg)
+		if err != nil {
+			return err
+		}
+		c.removeFromGroup(cl)
+		return nil
+	case "exec", "exec-once":
+		return nil
+	default:
+		return errors.New("Invalid dispatcher")
+	}
+}
+
+func (c *Compositor) switchWorkspace(ws *workspace) {
+	if ws.ID < 0 {
+		c.openSpecial(ws)
+		return
+	}
+
+	m := c.monitor(ws.Monitor)
+	if m.ID != c.focusedMon {
+		c.focusedMon = m.ID
+		c.emit("focusedmon", m.Name, ws.Name)
+		c.emit("focusedmonv2", m.Name, strconv.Itoa(ws.ID))
+	}
+
+	old := c.workspace(m.Active)
+	if old != ws {
+		c.lastWorkspace = m.Active
+		m.Previous = m.Active
+		m.Active = ws.ID
+		c.emit("workspace", ws.Name)
+		c.emit("workspacev2", strconv.Itoa(ws.ID), ws.Name)
+	}
+	c.focusWindow(c.lastFocused(ws))
+	if old != nil && old != ws {
+		c.destroyIfEmpty(old)
+	}
+}
+
+func (c *Compositor) moveToWorkspace(cl *client, ws *workspace, silent bool) {
+	old := c.workspace(cl.Workspace)
+	if old == ws {
+		return
+	}
+	wasActive := c.activeWindow() == cl
+
+	cl.Workspace = ws.ID
+	c.emit("movewindow", short(cl.Address), ws.Name)
+	c.emit("movewindowv2", short(cl.Address), strconv.Itoa(ws.ID), ws.Name)
+
+	switch {
+	case !silent:
+		c.switchWorkspace(ws)
+		c.focusWindow(cl)
+	case wasActive:
+		c.focusWindow(c.lastFocused(old))
+	}
+	c.destroyIfEmpty(old)
+}
+
+// toggleSpecial opens the special workspace name on the focused monitor, or
+// closes it when it is already open there.
+func (c *Compositor) toggleSpecial(name string) {
+	m := c.focusedMonitor()
+	if m == nil {
+		return
+	}
+	full := "special:" + cmp.Or(name, "special")
+
+	if m.Special != 0 && c.workspace(m.Special).Name == full {
+		old := c.workspace(m.Special)
+		m.Special = 0
+		c.emit("activespecial", "", m.Name)
+		c.emit("activespecialv2", "", "", m.Name)
+		c.focusWindow(c.lastFocused(c.workspace(m.Active)))
+		c.destroyIfEmpty(old)
+		return
+	}
+
+	ws := c.workspaceByName(full)
+	if ws == nil {
+		ws = c.createWorkspace(c.freeSpecialID(), full, m)
+	}
+	c.openSpecial(ws)
+}
+
+// openSpecial shows the special workspace ws on the focused monitor. Unlike
+// toggleSpecial it keeps ws open when it is already shown.
+func (c *Compositor) openSpecial(ws *workspace) {
+	m := c.focusedMonitor()
+	if m == nil {
+		return
+	}
+	if m.Special != ws.ID {
+		// a special workspace is shown on one monitor at a time
+		for _, other := range c.monitors {
+			if other.Special == ws.ID {
+				other.Special = 0
+				c.emit("activespecial", "", other.Name)
+				c.emit("activespecialv2", "", "", other.Name)
+			}
+		}
+		ws.Monitor = m.ID
+		m.Special = ws.ID
+		c.emit("activespecial", ws.Name, m.Name)
+		c.emit("activespecialv2", strconv.Itoa(ws.ID), ws.Name, m.Name)
+	}
+	c.focusWindow(c.lastFocused(ws))
+}
+
+func (c *Compositor) focusMonitor(m *monitor) {
+	c.focusedMon = m.ID
+	ws := c.workspace(m.Active)
+	c.emit("focusedmon", m.Name, ws.Name)
+	c.emit("focusedmonv2", m.Name, strconv.Itoa(ws.ID))
+	c.focusWindow(c.lastFocused(ws))
+}
+
+// focusWindow focuses cl, nil unfocuses all windows.
+func (c *Compositor) focusWindow(cl *client) {
+	if c.activeWindow() != cl {
+		c.setFocus(cl)
+	}
+}
+
+// setFocus focuses cl and emits the focus events even if it is unchanged.
+func (c *Compositor) setFocus(cl *client) {
+	if cl == nil {
+		c.focused = ""
+		c.emit("activewindow", ",")
+		c.emit("activewindowv2", "")
+		return
+	}
+	c.focused = cl.Address
+	c.history = slices.DeleteFunc(c.history, func(a string) bool {
+		return a == cl.Address
+	})
+	c.history = slices.Insert(c.history, 0, cl.Address)
+	c.emit("activewindow", cl.Class, cl.Title)
+	c.emit("activewindowv2", short(cl.Address))
+}
+
+func (c *Compositor) closeWindow(cl *client) {
+	wasActive := c.activeWindow() == cl
+	c.removeFromGroup(cl)
+	c.clients = slices.DeleteFunc(c.clients, func(o *client) bool {
+		return o == cl
+	})
+	c.history = slices.DeleteFunc(c.history, func(a string) bool {
+		return a == cl.Address
+	})
+	c.emit("closewindow", short(cl.Address))
+
+	ws := c.workspace(cl.Workspace)
+	if wasActive {
+		c.setFocus(c.lastFocused(ws))
+	}
+	c.destroyIfEmpty(ws)
+}
+
+func (c *Compositor) toggleGroup() error {
+	cl := c.activeWindow()
+	if cl == nil {
+		return errors.New("no active window")
+	}
+
+	if len(cl.Grouped) == 0 {
+		cl.Grouped = []string{cl.Address}
+		c.emit("togglegroup", "1", short(cl.Address))
+		return nil
+	}
+
+	members := cl.Grouped
+	args := []string{"0"}
+	for _, addr := range members {
+		args = append(args, short(addr))
+		if m := c.clientByAddress(addr); m != nil {
+			m.Grouped = nil
+		}
+	}
+	c.emit("togglegroup", args...)
+	return nil
+}
+
+// moveIntoGroup moves the active window into the group of the window next to
+// it. The simulator has no layout: the tiled windows of a workspace are laid
+// out from left to right in the order they were opened, so l and u select the
+// previous window and r and d the next one.
+func (c *Compositor) moveIntoGroup(dir string) error {
+	cl := c.activeWindow()
+	if cl == nil {
+		return errors.New("no active window")
+	}
+	var row []*client
+	for _, o := range c.clients {
+		if o.Workspace == cl.Workspace && !o.Floating {
+			row = append(row, o)
+		}
+	}
+	i := slices.Index(row, cl)
+	switch dir {
+	case "l", "u":
+		i--
+	case "r", "d":
+		i++
+	default:
+		return fmt.Errorf("invalid direction %q", dir)
+	}
+	if i < 0 || i >= len(row) || len(row[i].Grouped) == 0 {
+		return errors.New("no group in direction " + dir)
+	}
+
+	target := row[i]
+	c.removeFromGroup(cl)
+	members := append(slices.Clone(target.Grouped), cl.Address)
+	for _, addr := range members {
+		if m := c.clientByAddress(addr); m != nil {
+			m.Grouped = slices.Clone(members)
+		}
+	}
+	c.emit("moveintogroup", short(cl.Address))
+	return nil
+}
+
+func (c *Compositor) removeFromGroup(cl *client) {
+	if len(cl.Grouped) == 0 {
+		return
+	}
+	rest := slices.DeleteFunc(slices.Clone(cl.Grouped), func(a string) bool {
+		return a == cl.Address
+	})
+	for _, addr := range rest {
+		if m := c.clientByAddress(addr); m != nil {
+			m.Grouped = slices.Clone(rest)
+		}
+	}
+	cl.Grouped = nil
+	c.emit("moveoutofgroup", short(cl.Address))
+}
+
+func (c *Compositor) createWorkspace(
+	id int,
+	name string,
+	m *monitor,
+) *workspace {
+	if name == "" {
+		name = strconv.Itoa(id)
+	}
+	ws := &workspace{ID: id, Name: name, Monitor: m.ID}
+	c.workspaces = append(c.workspaces, ws)
+	slices.SortFunc(c.workspaces, func(a, b *workspace) int {
+		return a.ID - b.ID
+	})
+	c.emit("createworkspace", ws.Name)
+	c.emit("createworkspacev2", strconv.Itoa(ws.ID), ws.Name)
+	return ws
+}
+
+// destroyIfEmpty removes ws when it has no windows and is not visible.
+func (c *Compositor) destroyIfEmpty(ws *workspace) {
+	if ws == nil || c.workspace(ws.ID) != ws {
+		return
+	}
+	if c.visible(ws) || c.windowCount(ws) > 0 {
+		return
+	}
+	c.workspaces = slices.DeleteFunc(c.workspaces, func(o *workspace) bool {
+		return o == ws
+	})
+	c.emit("destroyworkspace", ws.Name)
+	c.emit("destroyworkspacev2", strconv.Itoa(ws.ID), ws.Name)
+}
+
+// resolveWorkspace resolves a workspace argument, creating the workspace on
+// the focused monitor when create is true and it does not exist.
+func (c *Compositor) resolveWorkspace(arg string, create bool) (
+	*workspace,
+	error,
+) {
+	m := c.focusedMonitor()
+	if m == nil {
+		return nil, errors.New("no monitor")
+	}
+
+	id, name := 0, ""
+	switch {
+	case arg == "previous":
+		id = c.lastWorkspace
+	case arg == "previous_per_monitor":
+		id = m.Previous
+	case strings.HasPrefix(arg, "empty"):
+		ws, err := c.emptyWorkspace(m, strings.TrimPrefix(arg, "empty"))
+		if err != nil {
+			return nil, err
+		}
+		id = ws
+	case strings.HasPrefix(arg, "name:"):
+		name = strings.TrimPrefix(arg, "name:")
+	case arg == "special" || strings.HasPrefix(arg, "special:"):
+		name = "special:" + cmp.Or(strings.TrimPrefix(arg, "special:"), "special")
+	case strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-"):
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace %q", arg)
+		}
+		id = m.Active + n
+	case len(arg) > 1 && strings.ContainsRune("mre", rune(arg[0])):
+		ws, err := c.relativeWorkspace(m, arg)
+		if err != nil {
+			return nil, err
+		}
+		id = ws
+	default:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace %q", arg)
+		}
+		id = n
+	}
+
+	var ws *workspace
+	if name != "" {
+		ws = c.workspaceByName(name)
+	} else {
+		ws = c.workspace(id)
+	}
+	if ws != nil {
+		return ws, nil
+	}
+	if !create || (name == "" && id <= 0) {
+		return nil, fmt.Errorf("invalid workspace %q", arg)
+	}
+	switch {
+	case strings.HasPrefix(name, "special:"):
+		return c.createWorkspace(c.freeSpecialID(), name, m), nil
+	case name != "":
+		return c.createWorkspace(c.freeNamedID(), name, m), nil
+	default:
+		return c.createWorkspace(id, "", m), nil
+	}
+}
+
+// emptyWorkspace resolves the id of empty, emptym, emptyn and emptymn. flags
+// is the part after "empty": m limits the search to workspaces that are free
+// or empty on m, n starts it after the active workspace.
+func (c *Compositor) emptyWorkspace(m *monitor, flags string) (int, error) {
+	onMonitor, next := false, false
+	for _, f := range flags {
+		switch f {
+		case 'm':
+			onMonitor = true
+		case 'n':
+			next = true
+		default:
+			return 0, fmt.Errorf("invalid workspace %q", "empty"+flags)
+		}
+	}
+
+	id := 1
+	if next {
+		id = max(m.Active+1, 1)
+	}
+	for ; ; id++ {
+		ws := c.workspace(id)
+		if ws == nil {
+			return id, nil
+		}
+		if onMonitor && ws.Monitor == m.ID && c.windowCount(ws) == 0 {
+			return id, nil
+		}
+	}
+}
+
+// relativeWorkspace resolves m+n, r+n and e+n among the existing workspaces
+// and the m~n, r~n and e~n indexes, which count from 1.
+func (c *Compositor) relativeWorkspace(m *monitor, arg string) (int, error) {
+	kind, rest := arg[0], arg[1:]
+	index := strings.HasPrefix(rest, "~")
+	n, err := strconv.Atoi(strings.TrimPrefix(rest, "~"))
+	if err != nil || (index && n < 1) {
+		return 0, fmt.Errorf("invalid workspace %q", arg)
+	}
+	if kind == 'r' && !index {
+		return m.Active + n, nil
+	}
+	if kind == 'r' {
+		// count the free ids too, they are empty workspaces
+		for id := 1; ; id++ {
+			if ws := c.workspace(id); ws == nil || ws.Monitor == m.ID {
+				if n--; n == 0 {
+					return id, nil
+				}
+			}
+		}
+	}
+
+	var ids []int
+	for _, ws := range c.workspaces {
+		if ws.ID > 0 && (kind == 'e' || ws.Monitor == m.ID) {
+			ids = append(ids, ws.ID)
+		}
+	}
+	if index {
+		if n > len(ids) {
+			return 0, fmt.Errorf("invalid workspace %q", arg)
+		}
+		return ids[n-1], nil
+	}
+	i := slices.Index(ids, m.Active)
+	if i < 0 || len(ids) == 0 {
+		return m.Active, nil
+	}
+	i = ((i+n)%len(ids) + len(ids)) % len(ids)
+	return ids[i], nil
+}
+
+// resolveWindow resolves a window selector, "" is the active window.
+func (c *Compositor) resolveWindow(arg string) (*client, error) {
+	if arg == "" || arg == "activewindow" {
+		if cl := c.activeWindow(); cl != nil {
+			return cl, nil
+		}
+		return nil, errors.New("no active window")
+	}
+
+	prefix, value, ok := strings.Cut(arg, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid window selector %q", arg)
+	}
+
+	var match func(*client) bool
+	switch prefix {
+	case "address":
+		match = func(cl *client) bool { return cl.Address == value }
+	case "pid":
+		pid, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid %q", value)
+		}
+		match = func(cl *client) bool { return cl.PID == pid }
+	case "class", "initialclass", "title", "initialtitle":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q", value)
+		}
+		match = func(cl *client) bool {
+			switch prefix {
+			case "class":
+				return re.MatchString(cl.Class)
+			case "initialclass":
+				return re.MatchString(cl.InitialClass)
+			case "title":
+				return re.MatchString(cl.Title)
+			default:
+				return re.MatchString(cl.InitialTitle)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid window selector %q", arg)
+	}
+
+	for _, cl := range c.clients {
+		if match(cl) {
+			return cl, nil
+		}
+	}
+	return nil, errors.New("no such window")
+}
+
+func (c *Compositor) freeWorkspaceID() int {
+	id := 1
+	for c.workspace(id) != nil {
+		id++
+	}
+	return id
+}
+
+// freeSpecialID returns the id for a special workspace, counting up from
+// specialWorkspaceStart.
+func (c *Compositor) freeSpecialID() int {
+	id := specialWorkspaceStart
+	for c.workspace(id) != nil {
+		id++
+	}
+	return id
+}
+
+// freeNamedID returns the id for a named workspace. Hyprland gives them ids
+// counting down from -1337.
+func (c *Compositor) freeNamedID() int {
+	id := -1337
+	for c.workspace(id) != nil {
+		id--
+	}
+	return id
+}
+
+func (c *Compositor) visible(ws *workspace) bool {
+	for _, m := range c.monitors {
+		if m.Active == ws.ID || m.Special == ws.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Compositor) windowCount(ws *workspace) int {
+	n := 0
+	for _, cl := range c.clients {
+		if cl.Workspace == ws.ID {
+			n++
+		}
+	}
+	return n
+}
+
+// lastFocused returns the most recently focused window on ws.
+func (c *Compositor) lastFocused(ws *workspace) *client {
+	if ws == nil {
+		return nil
+	}
+	for _, addr := range c.history {
+		if cl := c.clientByAddress(addr); cl != nil && cl.Workspace == ws.ID {
+			return cl
+		}
+	}
+	for _, cl := range c.clients {
+		if cl.Workspace == ws.ID {
+			return cl
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) activeWindow() *client {
+	return c.clientByAddress(c.focused)
+}
+
+func (c *Compositor) focusedMonitor() *monitor {
+	return c.monitor(c.focusedMon)
+}
+
+func (c *Compositor) monitor(id int) *monitor {
+	for _, m := range c.monitors {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) monitorByName(name string) *monitor {
+	for _, m := range c.monitors {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) workspace(id int) *workspace {
+	for _, ws := range c.workspaces {
+		if ws.ID == id {
+			return ws
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) workspaceByName(name string) *workspace {
+	for _, ws := range c.workspaces {
+		if ws.Name == name {
+			return ws
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) clientByAddress(addr string) *client {
+	if addr == "" {
+		return nil
+	}
+	for _, cl := range c.clients {
+		if cl.Address == addr {
+			return cl
+		}
+	}
+	return nil
+}
+
+func (c *Compositor) jsonHandler(fn func() any) HandlerFunc {
+	return func(string) string {
+		c.mu.Lock()
+		v := fn()
+		c.mu.Unlock()
+
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(data)
+	}
+}
+
+func (c *Compositor) simpleWorkspace(id int) map[string]any {
+	ws := c.workspace(id)
+	if ws == nil {
+		return map[string]any{"id": 0, "name": ""}
+	}
+	return map[string]any{"id": ws.ID, "name": ws.Name}
+}
+
+func (c *Compositor) monitorsJSON() any {
+	monitors := make([]map[string]any, 0, len(c.monitors))
+	for _, m := range c.monitors {
+		monitors = append(monitors, map[string]any{
+			"id":               m.ID,
+			"name":             m.Name,
+			"description":      m.Description,
+			"make":             "hyprlandtest",
+			"model":            m.Name,
+			"serial":           "",
+			"width":            m.Width,
+			"height":           m.Height,
+			"refreshRate":      60.0,
+			"x":                m.X,
+			"y":                0,
+			"activeWorkspace":  c.simpleWorkspace(m.Active),
+			"specialWorkspace": c.simpleWorkspace(m.Special),
+			"reserved":         []int{0, 0, 0, 0},
+			"scale":            1.0,
+			"transform":        0,
+			"focused":          m.ID == c.focusedMon,
+			"dpmsStatus":       true,
+			"vrr":              false,
+			"solitary":         "0",
+			"activelyTearing":  false,
+			"directScanoutTo":  "0",
+			"disabled":         false,
+			"currentFormat":    "XRGB8888",
+			"mirrorOf":         "none",
+			"availableModes":   []string{},
+		})
+	}
+	return monitors
+}
+
+func (c *Compositor) workspaceJSON(ws *workspace) map[string]any {
+	last := c.lastFocused(ws)
+	lastAddr, lastTitle := "0x0", ""
+	if last != nil {
+		lastAddr, lastTitle = last.Address, last.Title
+	}
+	hasFullscreen := false
+	for _, cl := range c.clients {
+		if cl.Workspace == ws.ID && cl.Fullscreen != 0 {
+			hasFullscreen = true
+		}
+	}
+
+	monName := ""
+	if m := c.monitor(ws.Monitor); m != nil {
+		monName = m.Name
+	}
+	return map[string]any{
+		"id":              ws.ID,
+		"name":            ws.Name,
+		"monitor":         monName,
+		"monitorID":       ws.Monitor,
+		"windows":         c.windowCount(ws),
+		"hasfullscreen":   hasFullscreen,
+		"lastwindow":      lastAddr,
+		"lastwindowtitle": lastTitle,
+		"ispersistent":    false,
+	}
+}
+
+func (c *Compositor) workspacesJSON() any {
+	workspaces := make([]map[string]any, 0, len(c.workspaces))
+	for _, ws := range c.workspaces {
+		workspaces = append(workspaces, c.workspaceJSON(ws))
+	}
+	return workspaces
+}
+
+func (c *Compositor) clientJSON(cl *client) map[string]any {
+	ws := c.workspace(cl.Workspace)
+	grouped := cl.Grouped
+	if grouped == nil {
+		grouped = []string{}
+	}
+	return map[string]any{
+		"address":          cl.Address,
+		"mapped":           true,
+		"hidden":           false,
+		"at":               []int{0, 0},
+		"size":             []int{800, 600},
+		"workspace":        c.simpleWorkspace(cl.Workspace),
+		"floating":         cl.Floating,
+		"pseudo":           false,
+		"monitor":          ws.Monitor,
+		"class":            cl.Class,
+		"title":            cl.Title,
+		"initialClass":     cl.InitialClass,
+		"initialTitle":     cl.InitialTitle,
+		"pid":              cl.PID,
+		"xwayland":         false,
+		"pinned":           cl.Pinned,
+		"fullscreen":       cl.Fullscreen,
+		"fullscreenClient": cl.Fullscreen,
+		"grouped":          grouped,
+		"tags":             []string{},
+		"swallowing":       "0x0",
+		"focusHistoryID":   c.focusHistoryID(cl),
+		"inhibitingIdle":   false,
+		"xdgTag":           "",
+		"xdgDescription":   "",
+	}
+}
+
+func (c *Compositor) focusHistoryID(cl *client) int {
+	return slices.Index(c.history, cl.Address)
+}
+
+func (c *Compositor) clientsJSON() any {
+	clients := make([]map[string]any, 0, len(c.clients))
+	for _, cl := range c.clients {
+		clients = append(clients, c.clientJSON(cl))
+	}
+	return clients
+}
+
+func (c *Compositor) activeWindowJSON() any {
+	if cl := c.activeWindow(); cl != nil {
+		return c.clientJSON(cl)
+	}
+	return map[string]any{}
+}
+
+func (c *Compositor) activeWorkspaceJSON() any {
+	m := c.focusedMonitor()
+	if m == nil {
+		return map[string]any{}
+	}
+	return c.workspaceJSON(c.workspace(m.Active))
+}
+
+// short strips the 0x prefix, events carry addresses without it.
+func short(addr string) string {
+	return strings.TrimPrefix(addr, "0x")
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,420 @@
+package hyprlandtest_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland"
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+// events connects to the event socket and returns a function reading the
+// next n event lines.
+func events(t *testing.T, s *hyprlandtest.Server) func(n int) []string {
+	t.Helper()
+	conn, err := net.Dial("unix", s.EventSocket())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewScanner(conn)
+	return func(n int) []string {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var lines []string
+		for len(lines) < n && r.Scan() {
+			lines = append(lines, r.Text())
+		}
+		if len(lines) < n {
+			t.Fatalf("got events %q, want %d: %v", lines, n, r.Err())
+		}
+		return lines
+	}
+}
+
+func TestCompositor(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	next := events(t, s)
+
+	kitty, err := comp.OpenWindow(hyprlandtest.Window{
+		Class: "kitty",
+		Title: "shell",
+		PID:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := strings.TrimPrefix(kitty, "0x")
+	want := []string{
+		"openwindow>>" + addr + ",1,kitty,shell",
+		"activewindow>>kitty,shell",
+		"activewindowv2>>" + addr,
+	}
+	if got := next(3); !slices.Equal(got, want) {
+		t.Errorf("OpenWindow() events = %q, want %q", got, want)
+	}
+
+	c := hyprland.NewRequestClient()
+	d := c.Dispatch()
+	err = d.MoveToWorkspace(hyprland.WorkspaceByID(2), hyprland.WindowSelector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"createworkspace>>2",
+		"createworkspacev2>>2,2",
+		"movewindow>>" + addr + ",2",
+		"movewindowv2>>" + addr + ",2,2",
+		"workspace>>2",
+		"workspacev2>>2,2",
+		"destroyworkspace>>1",
+		"destroyworkspacev2>>1,1",
+	}
+	if got := next(len(want)); !slices.Equal(got, want) {
+		t.Errorf("MoveToWorkspace() events = %q, want %q", got, want)
+	}
+
+	clients, err := c.GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) != 1 || clients[0].Workspace.ID != 2 {
+		t.Errorf("GetClients() = %+v, want kitty on workspace 2", clients)
+	}
+	workspaces, err := c.GetWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 1 || workspaces[0].Windows != 1 {
+		t.Errorf("GetWorkspaces() = %+v, want only workspace 2", workspaces)
+	}
+
+	if err := d.ToggleSpecialWorkspace("scratch"); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"createworkspace>>special:scratch",
+		"createworkspacev2>>-99,special:scratch",
+		"activespecial>>special:scratch,DP-1",
+		"activespecialv2>>-99,special:scratch,DP-1",
+		"activewindow>>,",
+		"activewindowv2>>",
+	}
+	if got := next(len(want)); !slices.Equal(got, want) {
+		t.Errorf("ToggleSpecialWorkspace() events = %q, want %q", got, want)
+	}
+	monitors, err := c.GetMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitors[0].SpecialWorkspace.Name != "special:scratch" {
+		t.Errorf("special workspace = %+v", monitors[0].SpecialWorkspace)
+	}
+
+	if err := d.FocusWindow(hyprland.WindowByClass("kitty")); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"activewindow>>kitty,shell", "activewindowv2>>" + addr}
+	if got := next(len(want)); !slices.Equal(got, want) {
+		t.Errorf("FocusWindow() events = %q, want %q", got, want)
+	}
+
+	if err := d.ToggleGroup(); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(1); got[0] != "togglegroup>>1,"+addr {
+		t.Errorf("ToggleGroup() events = %q", got)
+	}
+
+	if err := d.KillActive(); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"moveoutofgroup>>" + addr,
+		"closewindow>>" + addr,
+		"activewindow>>,",
+		"activewindowv2>>",
+	}
+	if got := next(len(want)); !slices.Equal(got, want) {
+		t.Errorf("KillActive() events = %q, want %q", got, want)
+	}
+	if comp.ActiveWindow() != "" {
+		t.Errorf("ActiveWindow() = %q, want none", comp.ActiveWindow())
+	}
+
+	if err := d.Raw("nope"); err == nil {
+		t.Error("unknown dispatcher should fail")
+	}
+}
+
+func TestCompositorSlowListener(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	addr, err := comp.OpenWindow(hyprlandtest.Window{Class: "kitty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a listener that does not read fills its socket buffer
+	conn, err := net.Dial("unix", s.EventSocket())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	title := strings.Repeat("x", 64*1024)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ctx.Err() == nil {
+			if comp.SetTitle(addr, title) != nil {
+				return
+			}
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// queries are answered while the events are blocked
+	qctx, qcancel := context.WithTimeout(ctx, time.Second)
+	defer qcancel()
+	if _, err := hyprland.NewRequestClient().GetClientsContext(qctx); err != nil {
+		t.Errorf("GetClients() with a blocked listener failed: %v", err)
+	}
+
+	cancel()
+	conn.Close()
+	<-done
+}
+
+func TestCompositorMonitorIDs(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("A", 1920, 1080)
+	comp.AddMonitor("B", 1920, 1080)
+	comp.AddMonitor("C", 1920, 1080)
+	if err := comp.RemoveMonitor("A"); err != nil {
+		t.Fatal(err)
+	}
+	comp.AddMonitor("D", 1920, 1080)
+
+	monitors, err := hyprland.NewRequestClient().GetMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range monitors {
+		got = append(got, fmt.Sprintf("%s=%d", m.Name, m.ID))
+	}
+	if want := []string{"B=1", "C=2", "D=3"}; !slices.Equal(got, want) {
+		t.Errorf("monitor ids = %q, want %q", got, want)
+	}
+}
+
+func TestCompositorFullscreen(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	if _, err := comp.OpenWindow(hyprlandtest.Window{Class: "mpv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := hyprland.NewRequestClient()
+	d := c.Dispatch()
+	for _, step := range []struct {
+		mode hyprland.FullscreenMode
+		want int64
+	}{
+		{hyprland.FullscreenFull, 2},
+		{hyprland.FullscreenFull, 0},
+		{hyprland.FullscreenMaximize, 1},
+		{hyprland.FullscreenMaximize, 0},
+	} {
+		if err := d.Fullscreen(step.mode); err != nil {
+			t.Fatal(err)
+		}
+		clients, err := c.GetClients()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := clients[0].Fullscreen; got != step.want {
+			t.Errorf("Fullscreen(%d): client fullscreen = %d, want %d",
+				step.mode, got, step.want)
+		}
+	}
+}
+
+func TestCompositorSpecialWorkspace(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	addr, err := comp.OpenWindow(hyprlandtest.Window{Class: "kitty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := hyprland.NewRequestClient()
+	d := c.Dispatch()
+	scratch := hyprland.WorkspaceSpecial("scratch")
+	for _, step := range []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{
+			"ToggleSpecialWorkspace",
+			func() error { return d.ToggleSpecialWorkspace("scratch") },
+			"special:scratch",
+		},
+		{
+			"MoveToWorkspace",
+			func() error {
+				return d.MoveToWorkspace(scratch, hyprland.WindowByAddress(addr))
+			},
+			"special:scratch",
+		},
+		{
+			"Workspace",
+			func() error { return d.Workspace(scratch) },
+			"special:scratch",
+		},
+		{
+			"ToggleSpecialWorkspace",
+			func() error { return d.ToggleSpecialWorkspace("scratch") },
+			"",
+		},
+	} {
+		if err := step.call(); err != nil {
+			t.Fatalf("%s() failed: %v", step.name, err)
+		}
+		monitors, err := c.GetMonitors()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := monitors[0].SpecialWorkspace.Name; got != step.want {
+			t.Errorf("after %s special workspace = %q, want %q",
+				step.name, got, step.want)
+		}
+	}
+	if got := comp.ActiveWindow(); got != "" {
+		t.Errorf("active window = %q after closing the special workspace", got)
+	}
+}
+
+func TestCompositorGroups(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	var addrs []string
+	for _, class := range []string{"a", "b", "c"} {
+		addr, err := comp.OpenWindow(hyprlandtest.Window{Class: class})
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	c := hyprland.NewRequestClient()
+	d := c.Dispatch()
+	steps := []func() error{
+		func() error { return d.FocusWindow(hyprland.WindowByClass("a")) },
+		d.ToggleGroup,
+		func() error { return d.FocusWindow(hyprland.WindowByClass("b")) },
+		func() error { return d.Raw("moveintogroup", "l") },
+		func() error { return d.FocusWindow(hyprland.WindowByClass("c")) },
+		func() error { return d.Raw("moveintogroup", "l") },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d failed: %v", i, err)
+		}
+	}
+
+	clients, err := c.GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cl := range clients {
+		if !slices.Equal(cl.Grouped, addrs) {
+			t.Errorf("%s grouped = %q, want %q", cl.Class, cl.Grouped, addrs)
+		}
+	}
+
+	if err := d.Raw("moveintogroup", "r"); err == nil {
+		t.Error("moveintogroup without a group in direction should fail")
+	}
+	if err := d.Raw("moveoutofgroup"); err != nil {
+		t.Fatal(err)
+	}
+	clients, err = c.GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := clients[0].Grouped; !slices.Equal(got, addrs[:2]) {
+		t.Errorf("grouped after moveoutofgroup = %q, want %q", got, addrs[:2])
+	}
+}
+
+func TestCompositorWorkspaceSelectors(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	comp.AddMonitor("HDMI-A-1", 1920, 1080)
+	_, err := comp.OpenWindow(hyprlandtest.Window{Class: "kitty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = comp.OpenWindow(hyprlandtest.Window{Class: "foot", Workspace: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DP-1 has workspaces 1 and 4, HDMI-A-1 has workspace 2
+	d := hyprland.NewRequestClient().Dispatch()
+	steps := []struct {
+		sel  hyprland.WorkspaceSelector
+		want int
+	}{
+		{hyprland.WorkspaceEmpty(false, false), 3},
+		{hyprland.WorkspaceByID(4), 4},
+		{hyprland.WorkspacePreviousPerMonitor, 3},
+		{hyprland.WorkspaceByID(1), 1},
+		{hyprland.WorkspaceEmpty(false, true), 3},
+		{hyprland.WorkspaceEmpty(true, false), 3},
+		{hyprland.WorkspaceMonitorIndex(2, false), 3},
+		{hyprland.WorkspaceMonitorIndex(1, false), 1},
+		{hyprland.WorkspaceMonitorIndex(2, true), 3},
+		{hyprland.WorkspaceOpenIndex(2), 2},
+	}
+	for _, step := range steps {
+		if err := d.Workspace(step.sel); err != nil {
+			t.Fatalf("Workspace(%s) failed: %v", step.sel, err)
+		}
+		if got := comp.ActiveWorkspace(); got != step.want {
+			t.Errorf("Workspace(%s) = %d, want %d", step.sel, got, step.want)
+		}
+	}
+
+	if err := d.Raw("workspace", "m~9"); err == nil {
+		t.Error("Workspace(m~9) should fail")
+	}
+}
@@ -0,0 +1,51 @@
+package hyprlandtest
+
+import "strings"
+
+// queryResponses are the canned replies for JSON queries of an empty session
+var queryResponses = map[string]string{
+	"activewindow":    `{}`,
+	"activeworkspace": `{"id":1,"name":"1","monitor":"","monitorID":0}`,
+	"animations":      `[[],[]]`,
+	"binds":           `[]`,
+	"clients":         `[]`,
+	"cursorpos":       `{"x":0,"y":0}`,
+	"devices": `{"mice":[],"keyboards":[],"tablets":[],"touch":[],` +
+		`"switches":[]}`,
+	"instances":   `[]`,
+	"layers":      `{}`,
+	"monitors":    `[]`,
+	"plugin list": `[]`,
+	"version": `{"branch":"main","commit":"0000000","version":"0.50.1",` +
+		`"dirty":false,"commit_message":"","commit_date":"",` +
+		`"tag":"v0.50.1","commits":"0","flags":[]}`,
+	"workspaces": `[]`,
+}
+
+// okCommands are commands that reply "ok" by default
+var okCommands = []string{
+	"dispatch ", "keyword ", "notify ", "dismissnotify", "setprop ",
+	"plugin load ", "plugin unload ", "reload",
+}
+
+// DefaultResponse returns the reply used when no handler matches cmd. JSON
+// queries get the reply of an empty session, commands that change state reply
+// "ok" and everything else gets "unknown request", like hyprland.
+func DefaultResponse(cmd string) string {
+	if query, ok := strings.CutPrefix(cmd, "j/"); ok {
+		if resp, ok := queryResponses[query]; ok {
+			return resp
+		}
+		if strings.HasPrefix(query, "getoption ") {
+			return "no such option"
+		}
+		return "unknown request"
+	}
+
+	for _, prefix := range okCommands {
+		if strings.HasPrefix(cmd, prefix) {
+			return "ok"
+		}
+	}
+	return "unknown request"
+}
@@ -0,0 +1,352 @@
+// Package hyprlandtest provides a fake hyprland instance for testing code
+// built on go-hyprland without a running compositor.
+//
+// A Server listens on fake .socket.sock and .socket2.sock sockets inside a
+// temporary XDG_RUNTIME_DIR and points HYPRLAND_INSTANCE_SIGNATURE at them,
+// so hyprland.NewRequestClient and hyprland.NewEventListener connect to it
+// without any extra setup.
+//
+// A Compositor can be put behind a Server to model monitors, workspaces and
+// windows. It applies dispatchers to its state and emits the events hyprland
+// would, which allows testing event driven code end-to-end.
+package hyprlandtest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Signature is the HYPRLAND_INSTANCE_SIGNATURE used by Server.
+const Signature = "hyprlandtest"
+
+const (
+	// batchPrefix marks a request as a batch of ';' separated commands
+	batchPrefix = "[[BATCH]]"
+	// batchDelimiter separates replies of a batch request
+	batchDelimiter = "\n\n\n"
+	// maxRequestSize is the largest request the server reads
+	maxRequestSize = 64 * 1024
+)
+
+// HandlerFunc returns the reply for a request command.
+type HandlerFunc func(cmd string) string
+
+type prefixHandler struct {
+	prefix string
+	fn     HandlerFunc
+}
+
+// Server is a fake hyprland instance. It is safe for concurrent use.
+type Server struct {
+	// Dir is the fake XDG_RUNTIME_DIR
+	Dir string
+
+	requestLn net.Listener
+	eventLn   net.Listener
+	wg        sync.WaitGroup
+
+	// emitMu keeps lines of concurrent EmitRaw calls in order
+	emitMu sync.Mutex
+
+	mu        sync.Mutex
+	exact     map[string]string
+	prefixes  []prefixHandler
+	commands  []string
+	listeners map[net.Conn]struct{}
+	// changed is closed and replaced whenever listeners change
+	changed chan struct{}
+}
+
+// NewServer starts a fake hyprland and sets XDG_RUNTIME_DIR and
+// HYPRLAND_INSTANCE_SIGNATURE for the duration of the test. The server is
+// closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	// unix socket paths are limited to 108 bytes, t.TempDir is too long
+	dir, err := os.MkdirTemp("", "hypr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	s, err := Start(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", Signature)
+	return s
+}
+
+// Start starts a fake hyprland instance in the runtime directory dir without
+// touching the environment. Use NewServer in tests.
+func Start(dir string) (*Server, error) {
+	instDir := filepath.Join(dir, "hypr", Signature)
+	if err := os.MkdirAll(instDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	lock := strconv.Itoa(os.Getpid()) + "\nwayland-test\n"
+	lockFile := filepath.Join(instDir, "hyprland.lock")
+	if err := os.WriteFile(lockFile, []byte(lock), 0o600); err != nil {
+		return nil, err
+	}
+
+	var err error
+	s := &Server{
+		Dir:       dir,
+		exact:     map[string]string{},
+		listeners: map[net.Conn]struct{}{},
+		changed:   make(chan struct{}),
+	}
+
+	s.requestLn, err = net.Listen("unix", s.RequestSocket())
+	if err != nil {
+		return nil, err
+	}
+	s.eventLn, err = net.Listen("unix", s.EventSocket())
+	if err != nil {
+		s.requestLn.Close()
+		return nil, err
+	}
+
+	s.wg.Go(s.serveRequests)
+	s.wg.Go(s.serveEvents)
+	return s, nil
+}
+
+// RequestSocket returns the path of the fake request socket.
+func (s *Server) RequestSocket() string {
+	return filepath.Join(s.Dir, "hypr", Signature, ".socket.sock")
+}
+
+// EventSocket returns the path of the fake event socket.
+func (s *Server) EventSocket() string {
+	return filepath.Join(s.Dir, "hypr", Signature, ".socket2.sock")
+}
+
+// Close stops the server and disconnects all event listeners.
+func (s *Server) Close() error {
+	err := errors.Join(s.requestLn.Close(), s.eventLn.Close())
+
+	s.mu.Lock()
+	for conn := range s.listeners {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// Handle makes the server reply response to the exact command cmd, e.g.
+// "j/clients" or "dispatch workspace 1".
+func (s *Server) Handle(cmd, response string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exact[cmd] = response
+}
+
+// HandleFunc makes the server reply with fn to every command starting with
+// prefix. Longer prefixes take precedence, the empty prefix matches every
+// command. Exact handlers registered with Handle take precedence over fn.
+func (s *Server) HandleFunc(prefix string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefixes = slices.DeleteFunc(s.prefixes, func(h prefixHandler) bool {
+		return h.prefix == prefix
+	})
+	s.prefixes = append(s.prefixes, prefixHandler{prefix: prefix, fn: fn})
+	slices.SortFunc(s.prefixes, func(a, b prefixHandler) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+}
+
+// Commands returns all commands received so far in order. Batch requests are
+// recorded as individual commands.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.commands)
+}
+
+// ResetCommands forgets the recorded commands.
+func (s *Server) ResetCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = nil
+}
+
+// Emit sends an event line to all connected event listeners.
+func (s *Server) Emit(event, data string) {
+	s.EmitRaw(event + ">>" + data)
+}
+
+// EmitRaw sends a raw line to all connected event listeners. Listeners that
+// fail to receive it are disconnected. The line is written without holding
+// s.mu, so listeners may send requests while it is being written.
+func (s *Server) EmitRaw(line string) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	s.mu.Lock()
+	conns := slices.Collect(maps.Keys(s.listeners))
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		if _, err := io.WriteString(conn, line+"\n"); err != nil {
+			conn.Close()
+			s.mu.Lock()
+			if _, ok := s.listeners[conn]; ok {
+				delete(s.listeners, conn)
+				s.notify()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Listeners returns the number of connected event listeners.
+func (s *Server) Listeners() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.listeners)
+}
+
+// WaitListeners blocks until at least n event listeners are connected or ctx
+// is done. Use it before Emit, as events are not buffered.
+func (s *Server) WaitListeners(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		count, changed := len(s.listeners), s.changed
+		s.mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d of %d listeners connected: %w", count, n, ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+// DisconnectListeners closes all event listener connections, like hyprland
+// exiting.
+func (s *Server) DisconnectListeners() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.listeners {
+		conn.Close()
+		delete(s.listeners, conn)
+	}
+	s.notify()
+}
+
+// notify wakes up WaitListeners. s.mu must be held.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) serveRequests() {
+	for {
+		conn, err := s.requestLn.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Go(func() {
+			defer conn.Close()
+			buf := make([]byte, maxRequestSize)
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			io.WriteString(conn, s.reply(string(buf[:n])))
+		})
+	}
+}
+
+func (s *Server) serveEvents() {
+	for {
+		conn, err := s.eventLn.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.listeners[conn] = struct{}{}
+		s.notify()
+		s.mu.Unlock()
+
+		// detect listeners that hang up
+		s.wg.Go(func() {
+			io.Copy(io.Discard, bufio.NewReader(conn))
+			s.mu.Lock()
+			if _, ok := s.listeners[conn]; ok {
+				delete(s.listeners, conn)
+				s.notify()
+			}
+			s.mu.Unlock()
+			conn.Close()
+		})
+	}
+}
+
+// reply returns the reply for a raw request, splitting batch requests.
+func (s *Server) reply(req string) string {
+	batch, ok := strings.CutPrefix(req, batchPrefix)
+	if !ok {
+		return s.handle(req)
+	}
+
+	cmds := strings.Split(batch, ";")
+	replies := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		if cmd = strings.TrimSpace(cmd); cmd != "" {
+			replies = append(replies, s.handle(cmd))
+		}
+	}
+	return strings.Join(replies, batchDelimiter)
+}
+
+func (s *Server) handle(cmd string) string {
+	s.mu.Lock()
+	s.commands = append(s.commands, cmd)
+	response, ok := s.exact[cmd]
+	var fn HandlerFunc
+	if !ok {
+		for _, h := range s.prefixes {
+			if strings.HasPrefix(cmd, h.prefix) {
+				fn = h.fn
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case ok:
+		return response
+	case fn != nil:
+		return fn(cmd)
+	default:
+		return DefaultResponse(cmd)
+	}
+}
@@ -0,0 +1,73 @@
+package hyprlandtest_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland"
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestServerRequests(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.Handle("j/clients", `[{"address":"0xabc","class":"kitty"}]`)
+	s.HandleFunc("dispatch exec", func(string) string { return "no" })
+
+	c := hyprland.NewRequestClient()
+	clients, err := c.GetClients()
+	if err != nil || len(clients) != 1 || clients[0].Class != "kitty" {
+		t.Fatalf("GetClients() = %+v, %v", clients, err)
+	}
+
+	if err := c.Dispatch().KillActive(); err != nil {
+		t.Errorf("KillActive() failed: %v", err)
+	}
+	if err := c.Dispatch().Exec("kitty"); err == nil {
+		t.Error("Exec() should fail")
+	}
+	_, err = c.GetOption("foo:bar")
+	if !errors.Is(err, hyprland.ErrInvalidArgument) {
+		t.Errorf("GetOption() error = %v, want ErrInvalidArgument", err)
+	}
+
+	want := []string{
+		"j/clients",
+		"dispatch killactive",
+		"dispatch exec kitty",
+		"j/getoption foo:bar",
+	}
+	if got := s.Commands(); !slices.Equal(got, want) {
+		t.Errorf("Commands() = %q, want %q", got, want)
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got := make(chan string, 1)
+	l := hyprland.NewEventListener()
+	l.OnWorkspaceV2(func(_ *hyprland.EventContext, _ int, name string) {
+		got <- name
+	})
+	go l.Listen(ctx)
+
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Emit("workspacev2", "2,web")
+
+	select {
+	case name := <-got:
+		if name != "web" {
+			t.Errorf("OnWorkspaceV2 got %q, want web", name)
+		}
+	case <-ctx.Done():
+		t.Fatal("event was not delivered")
+	}
+}
@@ -0,0 +1,210 @@
+package hyprland
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Instance is a hyprland instance found in $XDG_RUNTIME_DIR/hypr.
+type Instance struct {
+	// Signature is the HYPRLAND_INSTANCE_SIGNATURE of the instance
+	Signature string `json:"instance"`
+	// Time is the unix time when the instance was started
+	Time int64 `json:"time"`
+	// PID is the process id of hyprland
+	PID int64 `json:"pid"`
+	// WaylandSocket is the WAYLAND_DISPLAY of the instance
+	WaylandSocket string `json:"wl_socket"`
+	// dir is the directory the instance was read from, empty when it is in
+	// the current runtime directory
+	dir string
+}
+
+// lockFile is the file hyprland writes its pid and wayland socket to
+const lockFile = "hyprland.lock"
+
+// Dir returns the runtime directory of the instance.
+func (i Instance) Dir() (string, error) {
+	if i.dir != "" {
+		return i.dir, nil
+	}
+	dir, err := runtimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hypr", i.Signature), nil
+}
+
+// RequestSocket returns the request socket (socket) path of the instance.
+func (i Instance) RequestSocket() (SocketPath, error) {
+	return i.socket(".socket.sock")
+}
+
+// EventSocket returns the event socket (socket2) path of the instance.
+func (i Instance) EventSocket() (SocketPath, error) {
+	return i.socket(".socket2.sock")
+}
+
+func (i Instance) socket(name string) (SocketPath, error) {
+	dir, err := i.Dir()
+	if err != nil {
+		return "", err
+	}
+	return SocketPath(filepath.Join(dir, name)), nil
+}
+
+// Alive returns if the hyprland process of the instance is still running.
+// Instances left behind by a crashed hyprland are not alive.
+func (i Instance) Alive() bool {
+	if i.PID <= 0 {
+		return false
+	}
+	err := syscall.Kill(int(i.PID), 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Instances returns all hyprland instances found in $XDG_RUNTIME_DIR/hypr,
+// newest first. Use Instance.Alive to filter out dead instances.
+func Instances() ([]Instance, error) {
+	dir, err := runtimeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "hypr"))
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		inst, err := readInstance(filepath.Join(dir, "hypr", e.Name()))
+		if err != nil {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+
+	slices.SortFunc(instances, func(a, b Instance) int {
+		return cmp.Compare(b.Time, a.Time)
+	})
+	return instances, nil
+}
+
+// CurrentInstance returns the instance from HYPRLAND_INSTANCE_SIGNATURE. When
+// the env is not set or empty, e.g. in systemd units or SSH sessions, the
+// newest alive instance is returned instead.
+func CurrentInstance() (Instance, error) {
+	if his := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE"); his != "" {
+		inst := Instance{Signature: his}
+		if dir, err := inst.Dir(); err == nil {
+			if found, err := readInstance(dir); err == nil {
+				return found, nil
+			}
+		}
+		return inst, nil
+	}
+
+	instances, err := Instances()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Instance{}, err
+	}
+	for _, inst := range instances {
+		if inst.Alive() {
+			return inst, nil
+		}
+	}
+
+	return Instance{}, fmt.Errorf(
+		"%w: HYPRLAND_INSTANCE_SIGNATURE env is not set",
+		ErrNoInstance,
+	)
+}
+
+// readInstance reads the lock file in an instance directory. The lock file
+// contains the pid on the first line and the wayland socket on the second.
+func readInstance(dir string) (Instance, error) {
+	inst := Instance{Signature: filepath.Base(dir), dir: dir}
+
+	path := filepath.Join(dir, lockFile)
+	f, err := os.Open(path)
+	if err != nil {
+		return inst, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return inst, err
+	}
+	inst.Time = stat.ModTime().Unix()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		pid, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64)
+		if err != nil {
+			return inst, fmt.Errorf("invalid pid in %s: %w", path, err)
+		}
+		inst.PID = pid
+	}
+	if scanner.Scan() {
+		inst.WaylandSocket = strings.TrimSpace(scanner.Text())
+	}
+	return inst, scanner.Err()
+}
+
+func runtimeDir() (string, error) {
+	if dir, ok := os.LookupEnv("XDG_RUNTIME_DIR"); ok {
+		return dir, nil
+	}
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to get XDG_RUNTIME_DIR: %w", err)
+	}
+	return filepath.Join("/run/user", u.Uid), nil
+}
+
+// NewRequestClientFor creates a new RequestClient for the given instance.
+func NewRequestClientFor(inst Instance) (*RequestClient, error) {
+	socket, err := inst.RequestSocket()
+	if err != nil {
+		return nil, err
+	}
+	c := new(RequestClient)
+	c.Socket = socket
+	return c, nil
+}
+
+// Instance returns the instance c sends requests to.
+func (c *RequestClient) Instance() (Instance, error) {
+	socket, err := c.socket()
+	if err != nil {
+		return Instance{}, err
+	}
+	return readInstance(filepath.Dir(string(socket)))
+}
+
+// NewEventListenerFor creates a new EventListener for the given instance. The
+// listener stays on the instance when reconnecting, see ReconnectPolicy.
+func NewEventListenerFor(inst Instance) (*EventListener, error) {
+	socket, err := inst.EventSocket()
+	if err != nil {
+		return nil, err
+	}
+	l := NewEventListener()
+	l.Socket = socket
+	l.pinned = true
+	return l, nil
+}
@@ -0,0 +1,80 @@
+package hyprland
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func writeInstance(t *testing.T, dir, sig string, pid int, mod time.Time) {
+	t.Helper()
+	instDir := filepath.Join(dir, "hypr", sig)
+	if err := os.MkdirAll(instDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	lock := filepath.Join(instDir, lockFile)
+	data := strconv.Itoa(pid) + "\nwayland-1\n"
+	if err := os.WriteFile(lock, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(lock, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstances(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	// t.Setenv restores the original value after the test
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	os.Unsetenv("HYPRLAND_INSTANCE_SIGNATURE")
+
+	now := time.Now()
+	writeInstance(t, dir, "alive", os.Getpid(), now.Add(-time.Hour))
+	writeInstance(t, dir, "dead", 1<<30, now)
+
+	instances, err := Instances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Instances() returned %d instances, want 2", len(instances))
+	}
+	if instances[0].Signature != "dead" || instances[0].Alive() {
+		t.Errorf("newest instance should be dead: %+v", instances[0])
+	}
+	if instances[1].WaylandSocket != "wayland-1" || !instances[1].Alive() {
+		t.Errorf("oldest instance should be alive: %+v", instances[1])
+	}
+
+	inst, err := CurrentInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Signature != "alive" {
+		t.Errorf("CurrentInstance() = %q, want alive", inst.Signature)
+	}
+
+	socket, err := GetRequestSocket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SocketPath(filepath.Join(dir, "hypr", "alive", ".socket.sock"))
+	if socket != want {
+		t.Errorf("GetRequestSocket() = %q, want %q", socket, want)
+	}
+
+	// an empty signature is the same as an unset one
+	os.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	inst, err = CurrentInstance()
+	if err != nil || inst.Signature != "alive" {
+		t.Errorf("CurrentInstance() = %q, %v, want alive", inst.Signature, err)
+	}
+	os.RemoveAll(filepath.Join(dir, "hypr", "alive"))
+	if _, err := CurrentInstance(); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("CurrentInstance() error = %v, want ErrNoInstance", err)
+	}
+}
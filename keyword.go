@@ -0,0 +1,151 @@
+package hyprland
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Keyword sets a config option at runtime, the same as 'hyprctl keyword name
+// value'. Changes are lost when the config is reloaded.
+func (c *RequestClient) Keyword(name, value string) error {
+	return c.KeywordContext(context.Background(), name, value)
+}
+
+// KeywordContext is like Keyword but aborts the request when ctx is done.
+func (c *RequestClient) KeywordContext(
+	ctx context.Context,
+	name, value string,
+) error {
+	if err := checkKeywordName(name); err != nil {
+		return err
+	}
+	return c.commandContext(ctx, keywordCommand(name, value))
+}
+
+// KeywordInt sets an integer config option.
+func (c *RequestClient) KeywordInt(name string, value int) error {
+	return c.KeywordIntContext(context.Background(), name, value)
+}
+
+// KeywordIntContext is like KeywordInt but aborts the request when ctx is
+// done.
+func (c *RequestClient) KeywordIntContext(
+	ctx context.Context,
+	name string,
+	value int,
+) error {
+	return c.KeywordContext(ctx, name, strconv.Itoa(value))
+}
+
+// KeywordFloat sets a float config option.
+func (c *RequestClient) KeywordFloat(name string, value float64) error {
+	return c.KeywordFloatContext(context.Background(), name, value)
+}
+
+// KeywordFloatContext is like KeywordFloat but aborts the request when ctx
+// is done.
+func (c *RequestClient) KeywordFloatContext(
+	ctx context.Context,
+	name string,
+	value float64,
+) error {
+	return c.KeywordContext(ctx, name, formatFloat(value))
+}
+
+// KeywordBool sets a boolean config option.
+func (c *RequestClient) KeywordBool(name string, value bool) error {
+	return c.KeywordBoolContext(context.Background(), name, value)
+}
+
+// KeywordBoolContext is like KeywordBool but aborts the request when ctx is
+// done.
+func (c *RequestClient) KeywordBoolContext(
+	ctx context.Context,
+	name string,
+	value bool,
+) error {
+	return c.KeywordContext(ctx, name, formatBool(value))
+}
+
+// KeywordColor sets a color config option.
+func (c *RequestClient) KeywordColor(name string, value Color) error {
+	return c.KeywordColorContext(context.Background(), name, value)
+}
+
+// KeywordColorContext is like KeywordColor but aborts the request when ctx
+// is done.
+func (c *RequestClient) KeywordColorContext(
+	ctx context.Context,
+	name string,
+	value Color,
+) error {
+	return c.KeywordContext(ctx, name, value.String())
+}
+
+// KeywordGradient sets a gradient config option.
+func (c *RequestClient) KeywordGradient(name string, value Gradient) error {
+	return c.KeywordGradientContext(context.Background(), name, value)
+}
+
+// KeywordGradientContext is like KeywordGradient but aborts the request when
+// ctx is done.
+func (c *RequestClient) KeywordGradientContext(
+	ctx context.Context,
+	name string,
+	value Gradient,
+) error {
+	return c.KeywordContext(ctx, name, value.String())
+}
+
+// KeywordVec2 sets a vec2 config option.
+func (c *RequestClient) KeywordVec2(name string, x, y float64) error {
+	return c.KeywordVec2Context(context.Background(), name, x, y)
+}
+
+// KeywordVec2Context is like KeywordVec2 but aborts the request when ctx is
+// done.
+func (c *RequestClient) KeywordVec2Context(
+	ctx context.Context,
+	name string,
+	x, y float64,
+) error {
+	return c.KeywordContext(ctx, name, formatFloat(x)+" "+formatFloat(y))
+}
+
+// checkKeywordName rejects names hyprland would read as a different keyword
+// or command.
+func checkKeywordName(name string) error {
+	if name == "" {
+		return fmt.Errorf(
+			"%w: keyword name can not be empty",
+			ErrInvalidArgument,
+		)
+	}
+	if strings.ContainsFunc(name, unicode.IsSpace) ||
+		strings.ContainsRune(name, ';') {
+		return fmt.Errorf(
+			"%w: invalid keyword name: %q",
+			ErrInvalidArgument,
+			name,
+		)
+	}
+	return nil
+}
+
+func keywordCommand(name, value string) string {
+	return "keyword " + name + " " + value
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
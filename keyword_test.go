@@ -0,0 +1,102 @@
+package hyprland
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestKeyword(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	c := NewRequestClient()
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{
+			"Keyword",
+			func() error { return c.Keyword("general:layout", "master") },
+			"keyword general:layout master",
+		},
+		{
+			"KeywordInt",
+			func() error { return c.KeywordInt("general:gaps_in", -5) },
+			"keyword general:gaps_in -5",
+		},
+		{
+			"KeywordFloat",
+			func() error { return c.KeywordFloat("decoration:active_opacity", 0.9) },
+			"keyword decoration:active_opacity 0.9",
+		},
+		{
+			"KeywordBool",
+			func() error { return c.KeywordBool("animations:enabled", false) },
+			"keyword animations:enabled 0",
+		},
+		{
+			"KeywordColor",
+			func() error {
+				return c.KeywordColor("general:col.inactive_border", RGB(1, 2, 3))
+			},
+			"keyword general:col.inactive_border rgb(010203)",
+		},
+		{
+			"KeywordGradient",
+			func() error {
+				return c.KeywordGradient("general:col.active_border", Gradient{
+					Colors: []Color{RGB(255, 0, 0), RGBA(0, 0, 255, 128)},
+					Angle:  90,
+				})
+			},
+			"keyword general:col.active_border rgb(ff0000) rgba(0000ff80) 90deg",
+		},
+		{
+			"KeywordVec2",
+			func() error {
+				return c.KeywordVec2("decoration:shadow:offset", 2, -1.5)
+			},
+			"keyword decoration:shadow:offset 2 -1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("RequestClient."+tt.name, func(t *testing.T) {
+			s.ResetCommands()
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s() failed: %v", tt.name, err)
+			}
+			got := s.Commands()
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("%s() sent %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordRejected(t *testing.T) {
+	hyprlandtest.NewServer(t).Handle(
+		"keyword foo:bar 1",
+		"config option <foo:bar> does not exist.",
+	)
+	c := NewRequestClient()
+
+	err := c.KeywordInt("foo:bar", 1)
+	var respErr *ResponseError
+	if !errors.Is(err, ErrInvalidArgument) || !errors.As(err, &respErr) {
+		t.Fatalf("KeywordInt() error = %v, want ErrInvalidArgument", err)
+	}
+	want := "config option <foo:bar> does not exist."
+	if respErr.Message != want {
+		t.Errorf("ResponseError.Message = %q, want %q", respErr.Message, want)
+	}
+
+	for _, name := range []string{"", "general:gaps_in 5", "a;b", "a\nb"} {
+		if err := c.Keyword(name, "1"); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Keyword(%q) error = %v, want ErrInvalidArgument",
+				name, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package hyprland
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+)
+
+// HandlerFunc is a single handler invocation for an event: an On function, a
+// method of the EventHandler or a subscription.
+type HandlerFunc func(ctx *EventContext)
+
+// Middleware wraps every handler invocation of an EventListener. It can run
+// code before and after next, replace ctx or not call next at all to filter
+// the event for that handler:
+//
+//	l.Use(func(next hyprland.HandlerFunc) hyprland.HandlerFunc {
+//		return func(ctx *hyprland.EventContext) {
+//			start := time.Now()
+//			next(ctx)
+//			log.Println(ctx.Event, time.Since(start))
+//		}
+//	})
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// PanicError is reported by Recover when a handler panics.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("event handler panicked: %v", e.Value)
+}
+
+// Unwrap implements errors.Unwrap. It returns Value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Use appends middlewares that wrap every handler invocation of Listen. The
+// first middleware is the outermost. Events and Channel do not call handlers
+// and are not affected.
+func (l *EventListener) Use(mw ...Middleware) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.middleware = append(slices.Clip(l.middleware), mw...)
+}
+
+// call runs fn through the middlewares.
+func (cb *callbacks) call(ctx *EventContext, fn HandlerFunc) {
+	for _, mw := range slices.Backward(cb.middleware) {
+		fn = mw(fn)
+	}
+	fn(ctx)
+}
+
+// Recover returns a Middleware that recovers from panics in handlers and
+// reports them to fn as *PanicError, so a failing handler does not stop
+// Listen. The other handlers of the event still run. fn may be nil to drop
+// the panics.
+func Recover(fn OnErrorFunc) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *EventContext) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if fn != nil {
+					fn(ctx, &PanicError{Value: v, Stack: debug.Stack()})
+				}
+			}()
+			next(ctx)
+		}
+	}
+}
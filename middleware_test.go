@@ -0,0 +1,63 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got := make(chan string, 20)
+	errPanic := errors.New("widget broke")
+	l := NewEventListener()
+	l.Use(
+		Recover(func(_ *EventContext, err error) {
+			var perr *PanicError
+			if !errors.As(err, &perr) || !errors.Is(err, errPanic) {
+				t.Errorf("Recover() reported %v, want PanicError", err)
+			}
+			got <- "recovered"
+		}),
+		func(next HandlerFunc) HandlerFunc {
+			return func(ctx *EventContext) {
+				if ctx.RawData == "rofi" {
+					return
+				}
+				got <- "before"
+				next(ctx)
+			}
+		},
+	)
+	l.OnOpenLayer(func(*EventContext, string) {
+		panic(errPanic)
+	})
+	Subscribe(l, func(_ *EventContext, ev OpenLayerEvent) {
+		got <- "subscription:" + ev.Namespace
+	})
+
+	listen(ctx, t, l)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Emit("openlayer", "rofi")
+	s.Emit("openlayer", "waybar")
+
+	want := []string{
+		"before",
+		"recovered",
+		"before",
+		"subscription:waybar",
+	}
+	lines := receive(ctx, t, got, len(want))
+	if !slices.Equal(lines, want) {
+		t.Errorf("handler calls = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,89 @@
+package hyprland
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// NotifyIcon is the icon of a hyprland notification.
+type NotifyIcon int
+
+const (
+	// NotifyIconNone shows no icon
+	NotifyIconNone NotifyIcon = -1
+	// NotifyIconWarning is a warning icon
+	NotifyIconWarning NotifyIcon = 0
+	// NotifyIconInfo is an info icon
+	NotifyIconInfo NotifyIcon = 1
+	// NotifyIconHint is a hint icon
+	NotifyIconHint NotifyIcon = 2
+	// NotifyIconError is an error icon
+	NotifyIconError NotifyIcon = 3
+	// NotifyIconConfused is a confused icon
+	NotifyIconConfused NotifyIcon = 4
+	// NotifyIconOK is an ok icon
+	NotifyIconOK NotifyIcon = 5
+)
+
+// Notify shows a notification using hyprland's built-in notification system.
+// The zero Color uses the default color of the icon.
+func (c *RequestClient) Notify(
+	icon NotifyIcon,
+	duration time.Duration,
+	color Color,
+	message string,
+) error {
+	return c.NotifyContext(
+		context.Background(),
+		icon,
+		duration,
+		color,
+		message,
+	)
+}
+
+// NotifyContext is like Notify but aborts the request when ctx is done.
+func (c *RequestClient) NotifyContext(
+	ctx context.Context,
+	icon NotifyIcon,
+	duration time.Duration,
+	color Color,
+	message string,
+) error {
+	cmd := notifyCommand(icon, duration, color, message)
+	return c.commandContext(ctx, cmd)
+}
+
+// DismissNotify dismisses the n oldest notifications. n <= 0 dismisses all of
+// them.
+func (c *RequestClient) DismissNotify(n int) error {
+	return c.DismissNotifyContext(context.Background(), n)
+}
+
+// DismissNotifyContext is like DismissNotify but aborts the request when ctx
+// is done.
+func (c *RequestClient) DismissNotifyContext(
+	ctx context.Context,
+	n int,
+) error {
+	if n <= 0 {
+		n = -1
+	}
+	return c.commandContext(ctx, "dismissnotify "+strconv.Itoa(n))
+}
+
+func notifyCommand(
+	icon NotifyIcon,
+	duration time.Duration,
+	color Color,
+	message string,
+) string {
+	colorArg := "0"
+	if color != (Color{}) {
+		colorArg = color.String()
+	}
+	return "notify " + strconv.Itoa(int(icon)) + " " +
+		strconv.FormatInt(duration.Milliseconds(), 10) + " " +
+		colorArg + " " + message
+}
@@ -0,0 +1,18 @@
+package hyprland
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	got := notifyCommand(NotifyIconError, 5*time.Second, Color{}, "build failed")
+	if want := "notify 3 5000 0 build failed"; got != want {
+		t.Errorf("notifyCommand() = %q, want %q", got, want)
+	}
+
+	got = notifyCommand(NotifyIconNone, time.Second, RGB(0xff, 0, 0), "hi")
+	if want := "notify -1 1000 rgb(ff0000) hi"; got != want {
+		t.Errorf("notifyCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Plugin is a loaded hyprland plugin.
+type Plugin struct {
+	Name        string `json:"name"`
+	Author      string `json:"author"`
+	Handle      string `json:"handle"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// ListPlugins returns all loaded plugins.
+func (c *RequestClient) ListPlugins() ([]Plugin, error) {
+	return c.ListPluginsContext(context.Background())
+}
+
+// ListPluginsContext is like ListPlugins but aborts the request when ctx is
+// done.
+func (c *RequestClient) ListPluginsContext(
+	ctx context.Context,
+) ([]Plugin, error) {
+	data, err := c.rawRequestContext(ctx, "j/plugin list")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(string(data)) == "no plugins loaded" {
+		return []Plugin{}, nil
+	}
+
+	var plugins []Plugin
+	return plugins, decodeResponse("j/plugin list", data, &plugins)
+}
+
+// LoadPlugin loads the plugin shared object at path. path should be absolute.
+func (c *RequestClient) LoadPlugin(path string) error {
+	return c.LoadPluginContext(context.Background(), path)
+}
+
+// LoadPluginContext is like LoadPlugin but aborts the request when ctx is
+// done.
+func (c *RequestClient) LoadPluginContext(
+	ctx context.Context,
+	path string,
+) error {
+	if path == "" {
+		return errors.New("plugin path can not be empty")
+	}
+	return c.commandContext(ctx, "plugin load "+path)
+}
+
+// UnloadPlugin unloads the plugin loaded from path.
+func (c *RequestClient) UnloadPlugin(path string) error {
+	return c.UnloadPluginContext(context.Background(), path)
+}
+
+// UnloadPluginContext is like UnloadPlugin but aborts the request when ctx is
+// done.
+func (c *RequestClient) UnloadPluginContext(
+	ctx context.Context,
+	path string,
+) error {
+	if path == "" {
+		return errors.New("plugin path can not be empty")
+	}
+	return c.commandContext(ctx, "plugin unload "+path)
+}
@@ -0,0 +1,235 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	// EventConnected is a synthetic event sent by an EventListener with a
+	// ReconnectPolicy whenever it connects to the event socket.
+	// Args: SOCKETPATH
+	EventConnected Event = "listener:connected"
+
+	// EventDisconnected is a synthetic event sent by an EventListener with a
+	// ReconnectPolicy when the connection to the event socket drops.
+	// Args: REASON
+	EventDisconnected Event = "listener:disconnected"
+)
+
+// ConnectedEvent is sent when the listener connects to the event socket.
+type ConnectedEvent struct {
+	Socket SocketPath
+}
+
+// EventName implements EventValue.
+func (ConnectedEvent) EventName() Event {
+	return EventConnected
+}
+
+// DisconnectedEvent is sent when the connection to the event socket drops.
+type DisconnectedEvent struct {
+	// Reason is the error that closed the connection
+	Reason string
+}
+
+// EventName implements EventValue.
+func (DisconnectedEvent) EventName() Event {
+	return EventDisconnected
+}
+
+type (
+	// OnConnectedFunc is called when the listener connects to the event
+	// socket. socket is the path of the socket.
+	OnConnectedFunc func(ctx *EventContext, socket SocketPath)
+	// OnDisconnectedFunc is called when the connection to the event socket
+	// drops. reason is the error that closed the connection.
+	OnDisconnectedFunc func(ctx *EventContext, reason string)
+)
+
+// OnConnected sets the handler for Connected events
+func (l *EventListener) OnConnected(fn OnConnectedFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribed[EventConnected] = none
+	l.onConnected = fn
+}
+
+// OnDisconnected sets the handler for Disconnected events
+func (l *EventListener) OnDisconnected(fn OnDisconnectedFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribed[EventDisconnected] = none
+	l.onDisconnected = fn
+}
+
+// ConnectionHandler can be implemented by an EventHandler to receive the
+// ConnectedEvent and DisconnectedEvent of a listener with a ReconnectPolicy.
+type ConnectionHandler interface {
+	// Connected is called when the listener connects to the event socket.
+	// socket is the path of the socket.
+	Connected(ctx *EventContext, socket SocketPath)
+	// Disconnected is called when the connection to the event socket drops.
+	// reason is the error that closed the connection.
+	Disconnected(ctx *EventContext, reason string)
+}
+
+const (
+	defaultMinReconnectDelay = 100 * time.Millisecond
+	defaultMaxReconnectDelay = 10 * time.Second
+)
+
+// ReconnectPolicy configures how an EventListener reconnects when hyprland
+// restarts or the connection drops.
+//
+// When dialing fails, a listener created from the environment re-resolves the
+// socket to the newest alive instance, as a restarted hyprland has a new
+// instance signature. Listeners created by NewEventListenerFor keep retrying
+// their own instance.
+//
+// Around every connection the listener sends ConnectedEvent and
+// DisconnectedEvent to OnConnected, OnDisconnected, a handler implementing
+// ConnectionHandler, OnAllEvents and subscriptions.
+type ReconnectPolicy struct {
+	// MinDelay is the delay before the first attempt. It doubles after every
+	// failed attempt. Zero means 100ms.
+	MinDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero means 10s.
+	MaxDelay time.Duration
+	// MaxAttempts is the number of failed attempts in a row after which
+	// Listen gives up and returns the last error. Zero means no limit.
+	MaxAttempts int
+	// Resync is called after reconnecting, before any event of the new
+	// connection is handled. Use it to refresh state that missed events while
+	// disconnected, e.g. with State.Reconcile.
+	Resync func(ctx context.Context)
+}
+
+// delay returns the backoff after failures failed attempts in a row.
+func (p *ReconnectPolicy) delay(failures int) time.Duration {
+	d := p.MinDelay
+	if d <= 0 {
+		d = defaultMinReconnectDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxReconnectDelay
+	}
+	for range failures {
+		d *= 2
+		if d >= maxDelay {
+			return maxDelay
+		}
+	}
+	return min(d, maxDelay)
+}
+
+// listen is listenOnce that reconnects according to l.Reconnect.
+func (l *EventListener) listen(
+	ctx context.Context,
+	fn func(*EventContext) error,
+) error {
+	l.mu.Lock()
+	policy := l.Reconnect
+	closed := make(chan struct{})
+	l.closed = closed
+	l.mu.Unlock()
+
+	if policy == nil {
+		return l.listenOnce(ctx, fn, func(SocketPath) error { return nil })
+	}
+
+	wasConnected := false
+	failures := 0
+	for {
+		connected := false
+		err := l.listenOnce(ctx, fn, func(socket SocketPath) error {
+			connected = true
+			failures = 0
+			if wasConnected && policy.Resync != nil {
+				policy.Resync(ctx)
+			}
+			wasConnected = true
+			return fn(syntheticEvent(ctx, EventConnected, string(socket)))
+		})
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		select {
+		case <-closed:
+			return err
+		default:
+		}
+		if !errors.Is(err, ErrSocketUnavailable) &&
+			!errors.Is(err, ErrNoInstance) {
+			return err
+		}
+
+		if connected {
+			disconnected := syntheticEvent(ctx, EventDisconnected, err.Error())
+			if err := fn(disconnected); err != nil {
+				return err
+			}
+		} else {
+			failures++
+			if policy.MaxAttempts > 0 && failures >= policy.MaxAttempts {
+				return err
+			}
+			l.reresolve()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-closed:
+			return socketError(net.ErrClosed)
+		case <-time.After(policy.delay(failures)):
+		}
+	}
+}
+
+// reresolve points the listener to the newest alive instance, unless it is
+// pinned. A restarted hyprland has a new signature, so the old socket path
+// stays dead.
+func (l *EventListener) reresolve() {
+	l.mu.Lock()
+	pinned := l.pinned
+	l.mu.Unlock()
+	if pinned {
+		return
+	}
+
+	instances, err := Instances()
+	if err != nil {
+		return
+	}
+	for _, inst := range instances {
+		if !inst.Alive() {
+			continue
+		}
+		if socket, err := inst.EventSocket(); err == nil {
+			l.mu.Lock()
+			l.Socket = socket
+			l.mu.Unlock()
+		}
+		return
+	}
+}
+
+// syntheticEvent creates the context of an event generated by the listener.
+func syntheticEvent(
+	ctx context.Context,
+	event Event,
+	data string,
+) *EventContext {
+	return &EventContext{
+		Context:  ctx,
+		RawEvent: string(event) + EventSeparator + data,
+		Event:    event,
+		RawData:  data,
+		Time:     time.Now(),
+	}
+}
@@ -0,0 +1,274 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestReconnect(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan Event, 10)
+	l := NewEventListener()
+	l.Reconnect = &ReconnectPolicy{
+		MinDelay: 10 * time.Millisecond,
+		Resync: func(context.Context) {
+			events <- "resync"
+		},
+	}
+	l.SubscribeAll(func(_ *EventContext, ev EventValue) {
+		events <- ev.EventName()
+	})
+	errc := listen(ctx, t, l)
+
+	expect := func(want ...Event) {
+		t.Helper()
+		if got := receive(ctx, t, events, len(want)); !slices.Equal(got, want) {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+
+	expect(EventConnected)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.DisconnectListeners()
+	expect(EventDisconnected, "resync", EventConnected)
+
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Emit("openlayer", "waybar")
+	expect(EventOpenLayer)
+
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Listen() = %v, want context.Canceled", err)
+	}
+}
+
+// connectionHandler records the connection events. Its EventHandler methods
+// are not implemented and must not be called.
+type connectionHandler struct {
+	EventHandler
+	got chan<- string
+}
+
+var _ ConnectionHandler = connectionHandler{}
+
+func (connectionHandler) All(*EventContext) {}
+
+func (h connectionHandler) Connected(*EventContext, SocketPath) {
+	h.got <- "handler:connected"
+}
+
+func (h connectionHandler) Disconnected(*EventContext, string) {
+	h.got <- "handler:disconnected"
+}
+
+func TestReconnectHandlers(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got := make(chan string, 10)
+	l := NewEventListener()
+	l.Reconnect = &ReconnectPolicy{MinDelay: time.Millisecond}
+	l.OnConnected(func(_ *EventContext, socket SocketPath) {
+		if socket != SocketPath(s.EventSocket()) {
+			t.Errorf("OnConnected() socket = %s, want %s", socket, s.EventSocket())
+		}
+		got <- "on:connected"
+	})
+	l.OnDisconnected(func(_ *EventContext, reason string) {
+		if reason == "" {
+			t.Error("OnDisconnected() without a reason")
+		}
+		got <- "on:disconnected"
+	})
+	l.SetHandler(connectionHandler{got: got})
+	if !l.HasHandler(EventConnected) || !l.HasHandler(EventDisconnected) {
+		t.Error("HasHandler() = false for the connection events")
+	}
+	listen(ctx, t, l)
+
+	want := []string{"on:connected", "handler:connected"}
+	if lines := receive(ctx, t, got, 2); !slices.Equal(lines, want) {
+		t.Fatalf("handlers = %q, want %q", lines, want)
+	}
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.DisconnectListeners()
+	want = []string{
+		"on:disconnected", "handler:disconnected",
+		"on:connected", "handler:connected",
+	}
+	if lines := receive(ctx, t, got, 4); !slices.Equal(lines, want) {
+		t.Errorf("handlers after disconnect = %q, want %q", lines, want)
+	}
+}
+
+// listenConnected runs l until the test ends and sends the socket of every
+// connection to the returned channel.
+func listenConnected(
+	ctx context.Context,
+	t *testing.T,
+	l *EventListener,
+) <-chan SocketPath {
+	t.Helper()
+	connected := make(chan SocketPath, 10)
+	Subscribe(l, func(_ *EventContext, ev ConnectedEvent) {
+		connected <- ev.Socket
+	})
+	listen(ctx, t, l)
+	return connected
+}
+
+func TestReconnectInstanceChanged(t *testing.T) {
+	old := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l := NewEventListener()
+	l.Reconnect = &ReconnectPolicy{MinDelay: time.Millisecond}
+	connected := listenConnected(ctx, t, l)
+	want := SocketPath(old.EventSocket())
+	if got := receive(ctx, t, connected, 1)[0]; got != want {
+		t.Fatalf("connected to %s, want %s", got, want)
+	}
+
+	// hyprland restarts as a new instance
+	restarted := startInstance(t)
+	t.Setenv("XDG_RUNTIME_DIR", restarted.Dir)
+	old.Close()
+
+	want = SocketPath(restarted.EventSocket())
+	if got := receive(ctx, t, connected, 1)[0]; got != want {
+		t.Errorf("reconnected to %s, want %s", got, want)
+	}
+}
+
+func TestReconnectPinned(t *testing.T) {
+	pinned := startInstance(t)
+	t.Setenv("XDG_RUNTIME_DIR", pinned.Dir)
+	inst, err := CurrentInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewEventListenerFor(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := hyprlandtest.NewServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// giving up after a few attempts shows that none of the re-resolved
+	// attempts reached the other instance, which would accept the connection
+	l.Reconnect = &ReconnectPolicy{MinDelay: time.Millisecond, MaxAttempts: 3}
+	connected := make(chan SocketPath, 10)
+	Subscribe(l, func(_ *EventContext, ev ConnectedEvent) {
+		connected <- ev.Socket
+	})
+	errc := listen(ctx, t, l)
+	want := SocketPath(pinned.EventSocket())
+	if got := receive(ctx, t, connected, 1)[0]; got != want {
+		t.Fatalf("connected to %s, want %s", got, want)
+	}
+
+	pinned.Close()
+	err = receive(ctx, t, errc, 1)[0]
+	if !errors.Is(err, ErrSocketUnavailable) {
+		t.Fatalf("Listen() = %v, want ErrSocketUnavailable", err)
+	}
+	select {
+	case got := <-connected:
+		t.Errorf("connected to %s after the pinned instance closed", got)
+	default:
+	}
+	if n := other.Listeners(); n != 0 {
+		t.Errorf("other instance has %d listeners, want 0", n)
+	}
+
+	// the pinned instance comes back at the same path
+	restarted, err := hyprlandtest.Start(pinned.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { restarted.Close() })
+	listen(ctx, t, l)
+	if got := receive(ctx, t, connected, 1)[0]; got != want {
+		t.Errorf("reconnected to %s, want %s", got, want)
+	}
+}
+
+func TestReconnectClose(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l := NewEventListener()
+	l.Reconnect = &ReconnectPolicy{MinDelay: time.Millisecond}
+	errc := listen(ctx, t, l)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !l.IsConnected() {
+		t.Error("IsConnected() = false while listening")
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Listen() = %v, want net.ErrClosed", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Listen() reconnected after Close")
+	}
+	if l.IsConnected() {
+		t.Error("IsConnected() = true after Close")
+	}
+}
+
+func TestReconnectGiveUp(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	l := NewEventListener()
+	l.Reconnect = &ReconnectPolicy{
+		MinDelay:    time.Millisecond,
+		MaxAttempts: 3,
+	}
+	s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.Listen(ctx); !errors.Is(err, ErrSocketUnavailable) {
+		t.Errorf("Listen() = %v, want ErrSocketUnavailable", err)
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	p := &ReconnectPolicy{MinDelay: time.Second, MaxDelay: 5 * time.Second}
+	for failures, want := range []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	} {
+		if got := p.delay(failures); got != want {
+			t.Errorf("delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
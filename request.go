@@ -1,103 +1,499 @@
 package hyprland
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
-// RequestClient is used to send request to hyprland socket
+// RequestClient is used to send request to hyprland socket. Methods with the
+// Context suffix apply the deadline of ctx to dialing, writing and reading,
+// and abort the request when ctx is canceled.
+//
+// RequestClient is safe for concurrent use. Hyprland answers one request per
+// connection, so every request dials its own connection and closes it after
+// reading the reply; nothing but the socket path is shared between calls.
 type RequestClient struct {
-	// socket is the hyprland socket path
+	// Socket is the hyprland socket path. It is resolved from the current
+	// instance on the first request when empty.
 	Socket SocketPath
-	// conn is socket connection
-	conn net.Conn
+	// mu guards Socket while it is lazily resolved
+	mu sync.Mutex
 }
 
-// NewRequestClient creates a new RequestClient.
+// NewRequestClient creates a new RequestClient for the current instance. See
+// CurrentInstance.
 func NewRequestClient() *RequestClient {
 	c := new(RequestClient)
-	c.Socket = should(GetEventSocket())
+	c.Socket = should(GetRequestSocket())
 	return c
 }
 
+// Connect resolves the request socket and checks that it accepts
+// connections. Calling Connect is optional, every request connects on its
+// own.
 func (c *RequestClient) Connect() error {
-	if c.conn != nil {
-		return nil
-	}
+	return c.ConnectContext(context.Background())
+}
 
-	socket, err := GetRequestSocket()
+// ConnectContext is like Connect but gives up dialing when ctx is done.
+func (c *RequestClient) ConnectContext(ctx context.Context) error {
+	conn, err := c.dial(ctx)
 	if err != nil {
 		return err
 	}
-	c.Socket = socket
+	return conn.Close()
+}
+
+// Close is a no-op kept for compatibility. Connections are closed after each
+// request.
+func (c *RequestClient) Close() error {
+	return nil
+}
+
+// socket returns the socket path, resolving it when it is not set yet.
+func (c *RequestClient) socket() (SocketPath, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Socket == "" {
+		socket, err := GetRequestSocket()
+		if err != nil {
+			return "", err
+		}
+		c.Socket = socket
+	}
+	return c.Socket, nil
+}
 
-	conn, err := net.Dial("unix", string(socket))
+func (c *RequestClient) dial(ctx context.Context) (net.Conn, error) {
+	socket, err := c.socket()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c.conn = conn
-	return nil
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", string(socket))
+	if err != nil {
+		return nil, socketError(err)
+	}
+	return conn, nil
 }
 
-func (c *RequestClient) Close() error {
-	if c.conn == nil {
-		return nil
+// rawRequestContext writes cmd to the socket as is and returns the whole
+// response. It applies the deadline of ctx to the connection and aborts the
+// in-flight request when ctx is done.
+func (c *RequestClient) rawRequestContext(
+	ctx context.Context,
+	cmd string,
+) ([]byte, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	// unblock pending read and write as soon as ctx is canceled
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	data, err := exchange(conn, cmd)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// the deadline of ctx can fire on the socket before ctx itself
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	return data, err
+}
+
+func exchange(conn net.Conn, cmd string) ([]byte, error) {
+	if _, err := io.WriteString(conn, cmd); err != nil {
+		return nil, err
 	}
-	err := c.conn.Close()
-	c.conn = nil
-	return err
+	return io.ReadAll(conn)
 }
 
-func (c *RequestClient) request(cmd string, v any) error {
-	if err := c.Connect(); err != nil {
+func (c *RequestClient) requestContext(
+	ctx context.Context,
+	cmd string,
+	v any,
+) error {
+	data, err := c.rawRequestContext(ctx, "j/"+cmd)
+	if err != nil {
 		return err
 	}
-	defer c.Close()
+	return decodeResponse("j/"+cmd, data, v)
+}
+
+// decodeResponse decodes a JSON reply into v. Hyprland replies with plain
+// text like "unknown request" on failure, which is returned as ResponseError.
+// Replies that start like JSON but fail to decode, e.g. truncated ones, are
+// returned as DecodeError.
+func decodeResponse(cmd string, data []byte, v any) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) != 0 && trimmed[0] != '{' && trimmed[0] != '[' {
+		return &ResponseError{Command: cmd, Message: string(trimmed)}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &DecodeError{Source: cmd, Payload: data, Err: err}
+	}
+	return nil
+}
 
-	if _, err := fmt.Fprintf(c.conn, "j/%s", cmd); err != nil {
+// commandContext sends a non-query command and checks hyprland's reply for
+// "ok".
+func (c *RequestClient) commandContext(ctx context.Context, cmd string) error {
+	resp, err := c.rawRequestContext(ctx, cmd)
+	if err != nil {
 		return err
 	}
+	return checkResponse(cmd, resp)
+}
 
-	return json.NewDecoder(c.conn).Decode(v)
+// checkResponse turns hyprland's textual reply into an error. Hyprland answers
+// "ok" on success and a human readable message otherwise.
+func checkResponse(cmd string, resp []byte) error {
+	msg := strings.TrimSpace(string(resp))
+	switch msg {
+	case "ok":
+		return nil
+	case "":
+		return &DecodeError{
+			Source:  cmd,
+			Payload: resp,
+			Err:     errors.New("empty response"),
+		}
+	default:
+		return &ResponseError{Command: cmd, Message: msg}
+	}
 }
 
+// GetActiveWindow returns the focused window.
 func (c *RequestClient) GetActiveWindow() (Client, error) {
+	return c.GetActiveWindowContext(context.Background())
+}
+
+// GetActiveWindowContext is like GetActiveWindow but aborts the request when
+// ctx is done.
+func (c *RequestClient) GetActiveWindowContext(
+	ctx context.Context,
+) (Client, error) {
 	var w Client
-	return w, c.request("activewindow", &w)
+	return w, c.requestContext(ctx, "activewindow", &w)
 }
 
+// GetAnimations returns the configured animations and beziers.
 func (c *RequestClient) GetAnimations() (Animations, error) {
+	return c.GetAnimationsContext(context.Background())
+}
+
+// GetAnimationsContext is like GetAnimations but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetAnimationsContext(
+	ctx context.Context,
+) (Animations, error) {
 	var a Animations
-	return a, c.request("animations", &a)
+	return a, c.requestContext(ctx, "animations", &a)
 }
 
+// GetBinds returns all keybinds.
 func (c *RequestClient) GetBinds() (Binds, error) {
+	return c.GetBindsContext(context.Background())
+}
+
+// GetBindsContext is like GetBinds but aborts the request when ctx is done.
+func (c *RequestClient) GetBindsContext(ctx context.Context) (Binds, error) {
 	var a Binds
-	return a, c.request("binds", &a)
+	return a, c.requestContext(ctx, "binds", &a)
 }
 
+// GetCursorPosition returns the position of the cursor in the global layout.
 func (c *RequestClient) GetCursorPosition() (CursorPosition, error) {
+	return c.GetCursorPositionContext(context.Background())
+}
+
+// GetCursorPositionContext is like GetCursorPosition but aborts the request
+// when ctx is done.
+func (c *RequestClient) GetCursorPositionContext(
+	ctx context.Context,
+) (CursorPosition, error) {
 	var a CursorPosition
-	return a, c.request("cursorpos", &a)
+	return a, c.requestContext(ctx, "cursorpos", &a)
 }
 
+// GetClients returns all windows.
 func (c *RequestClient) GetClients() (Clients, error) {
+	return c.GetClientsContext(context.Background())
+}
+
+// GetClientsContext is like GetClients but aborts the request when ctx is done.
+func (c *RequestClient) GetClientsContext(
+	ctx context.Context,
+) (Clients, error) {
 	var clients Clients
-	return clients, c.request("clients", &clients)
+	return clients, c.requestContext(ctx, "clients", &clients)
 }
 
+// GetMonitors returns all monitors.
 func (c *RequestClient) GetMonitors() (Monitors, error) {
+	return c.GetMonitorsContext(context.Background())
+}
+
+// GetMonitorsContext is like GetMonitors but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetMonitorsContext(
+	ctx context.Context,
+) (Monitors, error) {
 	var m Monitors
-	return m, c.request("monitors", &m)
+	return m, c.requestContext(ctx, "monitors", &m)
 }
 
+// GetWorkspaces returns all workspaces.
 func (c *RequestClient) GetWorkspaces() (Workspaces, error) {
+	return c.GetWorkspacesContext(context.Background())
+}
+
+// GetWorkspacesContext is like GetWorkspaces but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetWorkspacesContext(
+	ctx context.Context,
+) (Workspaces, error) {
 	var w Workspaces
-	return w, c.request("workspaces", &w)
+	return w, c.requestContext(ctx, "workspaces", &w)
 }
 
+// GetActiveWorkspace returns the workspace of the focused monitor.
 func (c *RequestClient) GetActiveWorkspace() (Workspace, error) {
+	return c.GetActiveWorkspaceContext(context.Background())
+}
+
+// GetActiveWorkspaceContext is like GetActiveWorkspace but aborts the request
+// when ctx is done.
+func (c *RequestClient) GetActiveWorkspaceContext(
+	ctx context.Context,
+) (Workspace, error) {
 	var w Workspace
-	return w, c.request("activeworkspace", &w)
+	return w, c.requestContext(ctx, "activeworkspace", &w)
+}
+
+// GetDevices returns the connected input devices.
+func (c *RequestClient) GetDevices() (Devices, error) {
+	return c.GetDevicesContext(context.Background())
+}
+
+// GetDevicesContext is like GetDevices but aborts the request when ctx is done.
+func (c *RequestClient) GetDevicesContext(
+	ctx context.Context,
+) (Devices, error) {
+	var d Devices
+	return d, c.requestContext(ctx, "devices", &d)
+}
+
+// GetLayers returns the layer surfaces of every monitor.
+func (c *RequestClient) GetLayers() (Layers, error) {
+	return c.GetLayersContext(context.Background())
+}
+
+// GetLayersContext is like GetLayers but aborts the request when ctx is done.
+func (c *RequestClient) GetLayersContext(ctx context.Context) (Layers, error) {
+	var l Layers
+	return l, c.requestContext(ctx, "layers", &l)
+}
+
+// GetInstances returns the running hyprland instances known to hyprland.
+func (c *RequestClient) GetInstances() ([]Instance, error) {
+	return c.GetInstancesContext(context.Background())
+}
+
+// GetInstancesContext is like GetInstances but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetInstancesContext(
+	ctx context.Context,
+) ([]Instance, error) {
+	var i []Instance
+	return i, c.requestContext(ctx, "instances", &i)
+}
+
+// GetVersion returns the version of the running hyprland.
+func (c *RequestClient) GetVersion() (Version, error) {
+	return c.GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but aborts the request when ctx is done.
+func (c *RequestClient) GetVersionContext(
+	ctx context.Context,
+) (Version, error) {
+	var v Version
+	return v, c.requestContext(ctx, "version", &v)
+}
+
+// Supports returns if the running hyprland has the given capability.
+func (c *RequestClient) Supports(capability Capability) (bool, error) {
+	return c.SupportsContext(context.Background(), capability)
+}
+
+// SupportsContext is like Supports but aborts the request when ctx is done.
+func (c *RequestClient) SupportsContext(
+	ctx context.Context,
+	capability Capability,
+) (bool, error) {
+	v, err := c.GetVersionContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return v.Supports(capability), nil
+}
+
+// GetOption returns the current value of a config option, e.g.
+// "general:gaps_in".
+func (c *RequestClient) GetOption(name string) (Option, error) {
+	return c.GetOptionContext(context.Background(), name)
+}
+
+// GetOptionContext is like GetOption but aborts the request when ctx is done.
+func (c *RequestClient) GetOptionContext(
+	ctx context.Context,
+	name string,
+) (Option, error) {
+	var o Option
+	return o, c.requestContext(ctx, "getoption "+name, &o)
+}
+
+// GetOptionInt returns the value of an integer option.
+func (c *RequestClient) GetOptionInt(name string) (int64, error) {
+	return c.GetOptionIntContext(context.Background(), name)
+}
+
+// GetOptionIntContext is like GetOptionInt but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetOptionIntContext(
+	ctx context.Context,
+	name string,
+) (int64, error) {
+	return getOptionAs(ctx, c, name, OptionInt, func(o Option) int64 {
+		return *o.Int
+	})
+}
+
+// GetOptionBool returns the value of a boolean option.
+func (c *RequestClient) GetOptionBool(name string) (bool, error) {
+	return c.GetOptionBoolContext(context.Background(), name)
+}
+
+// GetOptionBoolContext is like GetOptionBool but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetOptionBoolContext(
+	ctx context.Context,
+	name string,
+) (bool, error) {
+	return getOptionAs(ctx, c, name, OptionInt, func(o Option) bool {
+		return *o.Int != 0
+	})
+}
+
+// GetOptionFloat returns the value of a float option.
+func (c *RequestClient) GetOptionFloat(name string) (float64, error) {
+	return c.GetOptionFloatContext(context.Background(), name)
+}
+
+// GetOptionFloatContext is like GetOptionFloat but aborts the request when ctx
+// is done.
+func (c *RequestClient) GetOptionFloatContext(
+	ctx context.Context,
+	name string,
+) (float64, error) {
+	return getOptionAs(ctx, c, name, OptionFloat, func(o Option) float64 {
+		return *o.Float
+	})
+}
+
+// GetOptionString returns the value of a string option.
+func (c *RequestClient) GetOptionString(name string) (string, error) {
+	return c.GetOptionStringContext(context.Background(), name)
+}
+
+// GetOptionStringContext is like GetOptionString but aborts the request when
+// ctx is done.
+func (c *RequestClient) GetOptionStringContext(
+	ctx context.Context,
+	name string,
+) (string, error) {
+	return getOptionAs(ctx, c, name, OptionString, func(o Option) string {
+		return *o.Str
+	})
+}
+
+// GetOptionVec2 returns the value of a vec2 option.
+func (c *RequestClient) GetOptionVec2(name string) ([2]float64, error) {
+	return c.GetOptionVec2Context(context.Background(), name)
+}
+
+// GetOptionVec2Context is like GetOptionVec2 but aborts the request when ctx is
+// done.
+func (c *RequestClient) GetOptionVec2Context(
+	ctx context.Context,
+	name string,
+) ([2]float64, error) {
+	return getOptionAs(ctx, c, name, OptionVec2, func(o Option) [2]float64 {
+		return *o.Vec2
+	})
+}
+
+// GetOptionCustom returns the textual value of a custom option, e.g.
+// gradients or css style gaps.
+func (c *RequestClient) GetOptionCustom(name string) (string, error) {
+	return c.GetOptionCustomContext(context.Background(), name)
+}
+
+// GetOptionCustomContext is like GetOptionCustom but aborts the request when
+// ctx is done.
+func (c *RequestClient) GetOptionCustomContext(
+	ctx context.Context,
+	name string,
+) (string, error) {
+	return getOptionAs(ctx, c, name, OptionCustom, func(o Option) string {
+		return *o.Custom
+	})
+}
+
+func getOptionAs[T any](
+	ctx context.Context,
+	c *RequestClient,
+	name string,
+	kind OptionKind,
+	get func(Option) T,
+) (T, error) {
+	var zero T
+	o, err := c.GetOptionContext(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	if o.Kind() != kind {
+		return zero, fmt.Errorf(
+			"option %s is %q, not %q",
+			name,
+			o.Kind(),
+			kind,
+		)
+	}
+	return get(o), nil
 }
@@ -1,12 +1,22 @@
 package hyprland
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
 )
 
 func dropVal[T any](_ T, err error) error { return err }
 
 func TestRequestClient(t *testing.T) {
+	hyprlandtest.NewServer(t)
 	c := NewRequestClient()
 
 	tests := []struct {
@@ -21,6 +31,9 @@ func TestRequestClient(t *testing.T) {
 		{"GetActiveWorkspace", dropVal(c.GetActiveWorkspace())},
 		{"GetBinds", dropVal(c.GetBinds())},
 		{"GetCursorPosition", dropVal(c.GetCursorPosition())},
+		{"GetDevices", dropVal(c.GetDevices())},
+		{"GetLayers", dropVal(c.GetLayers())},
+		{"GetVersion", dropVal(c.GetVersion())},
 	}
 
 	for _, tt := range tests {
@@ -31,3 +44,296 @@ func TestRequestClient(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOption(t *testing.T) {
+	hyprlandtest.NewServer(t).HandleFunc("", func(req string) string {
+		switch req {
+		case "j/getoption general:gaps_in":
+			return `{"option":"general:gaps_in","custom":"5 5 5 5","set":true}`
+		case "j/getoption decoration:rounding":
+			return `{"option":"decoration:rounding","int":10,"set":false}`
+		default:
+			return "no such option"
+		}
+	})
+	c := NewRequestClient()
+
+	gaps, err := c.GetOptionCustom("general:gaps_in")
+	if err != nil || gaps != "5 5 5 5" {
+		t.Errorf("GetOptionCustom() = %q, %v", gaps, err)
+	}
+
+	rounding, err := c.GetOptionInt("decoration:rounding")
+	if err != nil || rounding != 10 {
+		t.Errorf("GetOptionInt() = %d, %v", rounding, err)
+	}
+
+	if _, err := c.GetOptionFloat("decoration:rounding"); err == nil {
+		t.Error("GetOptionFloat() on int option should fail")
+	}
+
+	if _, err := c.GetOption("foo:bar"); err == nil {
+		t.Error("GetOption() on missing option should fail")
+	}
+}
+
+func TestLayersUnmarshal(t *testing.T) {
+	data := `{"DP-1":{"levels":{"0":[],"2":[{"address":"0x1","x":0,"y":0,` +
+		`"w":1920,"h":30,"namespace":"waybar","pid":42}]}}}`
+
+	var l Layers
+	if err := json.Unmarshal([]byte(data), &l); err != nil {
+		t.Fatal(err)
+	}
+
+	top := l["DP-1"][LayerTop]
+	if len(top) != 1 || top[0].Namespace != "waybar" || top[0].H != 30 {
+		t.Errorf("unexpected layers: %+v", l)
+	}
+	if _, ok := l.Find("waybar"); !ok {
+		t.Error("Find(waybar) failed")
+	}
+}
+
+func TestGetDevices(t *testing.T) {
+	hyprlandtest.NewServer(t).Handle("j/devices", `{
+		"mice": [{"address": "0x10", "name": "mouse", "defaultSpeed": 0.5}],
+		"keyboards": [
+			{"address": "0x20", "name": "power-button", "main": false},
+			{
+				"address": "0x21",
+				"name": "at-keyboard",
+				"layout": "us,de",
+				"active_keymap": "English (US)",
+				"capsLock": true,
+				"main": true
+			}
+		],
+		"tablets": [
+			{"address": "0x30", "name": "wacom"},
+			{
+				"address": "0x31",
+				"type": "tabletTool",
+				"belongsTo": {"address": "0x30", "name": "wacom"}
+			}
+		],
+		"touch": [],
+		"switches": [{"address": "0x40", "name": "lid"}]
+	}`)
+
+	d, err := NewRequestClient().GetDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Mice) != 1 || d.Mice[0].DefaultSpeed != 0.5 {
+		t.Errorf("Mice = %+v", d.Mice)
+	}
+	if len(d.Switches) != 1 || d.Switches[0].Name != "lid" {
+		t.Errorf("Switches = %+v", d.Switches)
+	}
+
+	kb, ok := d.MainKeyboard()
+	want := Keyboard{
+		Address:      "0x21",
+		Name:         "at-keyboard",
+		Layout:       "us,de",
+		ActiveKeymap: "English (US)",
+		CapsLock:     true,
+		Main:         true,
+	}
+	if !ok || kb != want {
+		t.Errorf("MainKeyboard() = %+v, %v, want %+v", kb, ok, want)
+	}
+	if _, ok := (Devices{Keyboards: d.Keyboards[:1]}).MainKeyboard(); ok {
+		t.Error("MainKeyboard() without a main keyboard should fail")
+	}
+
+	if len(d.Tablets) != 2 {
+		t.Fatalf("Tablets = %+v", d.Tablets)
+	}
+	if d.Tablets[0].BelongsTo != nil {
+		t.Errorf("Tablets[0].BelongsTo = %+v, want nil", d.Tablets[0].BelongsTo)
+	}
+	tool := d.Tablets[1]
+	owner := TabletOwner{Address: "0x30", Name: "wacom"}
+	if tool.Type != "tabletTool" || tool.BelongsTo == nil ||
+		*tool.BelongsTo != owner {
+		t.Errorf("Tablets[1] = %+v, want tool of %+v", tool, owner)
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	loaded := false
+	hyprlandtest.NewServer(t).HandleFunc("", func(req string) string {
+		switch req {
+		case "j/plugin list":
+			if !loaded {
+				return "no plugins loaded"
+			}
+			return `[{"name":"hyprbars","author":"Vaxry","handle":"0x1",` +
+				`"version":"1.0","description":"title bars"}]`
+		case "plugin load /tmp/hyprbars.so":
+			loaded = true
+			return "ok"
+		default:
+			return "could not find plugin"
+		}
+	})
+	c := NewRequestClient()
+
+	plugins, err := c.ListPlugins()
+	if err != nil || len(plugins) != 0 {
+		t.Fatalf("ListPlugins() = %v, %v", plugins, err)
+	}
+
+	if err := c.LoadPlugin("/tmp/hyprbars.so"); err != nil {
+		t.Fatalf("LoadPlugin() failed: %v", err)
+	}
+
+	plugins, err = c.ListPlugins()
+	if err != nil || len(plugins) != 1 || plugins[0].Name != "hyprbars" {
+		t.Fatalf("ListPlugins() = %v, %v", plugins, err)
+	}
+
+	if err := c.UnloadPlugin("/tmp/other.so"); err == nil {
+		t.Error("UnloadPlugin() of unknown plugin should fail")
+	}
+}
+
+func TestRequestClientContext(t *testing.T) {
+	release := make(chan struct{})
+	hyprlandtest.NewServer(t).HandleFunc("", func(string) string {
+		<-release
+		return "[]"
+	})
+	t.Cleanup(func() { close(release) })
+
+	c := NewRequestClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetClientsContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetClientsContext() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetClientsContext() returned after %v", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	window := WindowByAddress("0x1")
+	calls := []struct {
+		name string
+		err  error
+	}{
+		{"DispatchContext", c.DispatchContext(canceled).KillActive()},
+		{"KeywordIntContext", c.KeywordIntContext(canceled, "a:b", 1)},
+		{
+			"NotifyContext",
+			c.NotifyContext(canceled, NotifyIconInfo, time.Second, Color{}, "hi"),
+		},
+		{"DismissNotifyContext", c.DismissNotifyContext(canceled, 0)},
+		{"SetAlphaContext", c.SetAlphaContext(canceled, window, 0.5, false)},
+		{"LoadPluginContext", c.LoadPluginContext(canceled, "/a.so")},
+		{"UnloadPluginContext", c.UnloadPluginContext(canceled, "/a.so")},
+		{"GetOptionIntContext", dropVal(c.GetOptionIntContext(canceled, "a:b"))},
+	}
+	for _, call := range calls {
+		if !errors.Is(call.err, context.Canceled) {
+			t.Errorf("%s() error = %v, want context.Canceled", call.name, call.err)
+		}
+	}
+}
+
+func TestRequestClientConcurrent(t *testing.T) {
+	var requests atomic.Int64
+	hyprlandtest.NewServer(t).HandleFunc("", func(req string) string {
+		requests.Add(1)
+		if req == "j/clients" {
+			return `[{"address":"0x1"}]`
+		}
+		return "ok"
+	})
+
+	c := new(RequestClient) // socket is resolved lazily
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for range 32 {
+		wg.Go(func() {
+			clients, err := c.GetClients()
+			if err == nil && len(clients) != 1 {
+				err = errors.New("unexpected clients")
+			}
+			errs <- err
+		})
+		wg.Go(func() {
+			errs <- c.Dispatch().Workspace(WorkspaceByID(1))
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := requests.Load(); n != 64 {
+		t.Errorf("server received %d requests, want 64", n)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	hyprlandtest.NewServer(t).HandleFunc("", func(req string) string {
+		switch req {
+		case "j/workspaces":
+			return "unknown request"
+		case "j/clients":
+			return `[{"address":`
+		case "j/monitors":
+			return `{"id":"not a list"}`
+		default:
+			return "ok"
+		}
+	})
+	c := NewRequestClient()
+
+	if _, err := c.GetWorkspaces(); !errors.Is(err, ErrUnknownRequest) {
+		t.Errorf("GetWorkspaces() error = %v, want ErrUnknownRequest", err)
+	}
+
+	// a truncated reply is not an error message of hyprland
+	_, err := c.GetClients()
+	var decodeErr *DecodeError
+	if !errors.Is(err, ErrDecode) || !errors.As(err, &decodeErr) {
+		t.Fatalf("GetClients() error = %v, want ErrDecode", err)
+	}
+	if errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetClients() error = %v matches ErrInvalidArgument", err)
+	}
+	if string(decodeErr.Payload) != `[{"address":` {
+		t.Errorf("DecodeError.Payload = %q", decodeErr.Payload)
+	}
+
+	_, err = c.GetMonitors()
+	if !errors.Is(err, ErrDecode) || !errors.As(err, &decodeErr) {
+		t.Fatalf("GetMonitors() error = %v, want ErrDecode", err)
+	}
+	if string(decodeErr.Payload) != `{"id":"not a list"}` {
+		t.Errorf("DecodeError.Payload = %q", decodeErr.Payload)
+	}
+
+	c = &RequestClient{Socket: "/nonexistent/.socket.sock"}
+	if _, err := c.GetClients(); !errors.Is(err, ErrSocketUnavailable) {
+		t.Errorf("GetClients() error = %v, want ErrSocketUnavailable", err)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	os.Unsetenv("HYPRLAND_INSTANCE_SIGNATURE")
+	if _, err := new(RequestClient).GetClients(); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("GetClients() error = %v, want ErrNoInstance", err)
+	}
+}
@@ -149,3 +149,208 @@ type CursorPosition struct {
 	X int64 `json:"x"`
 	Y int64 `json:"y"`
 }
+
+// OptionKind is the type of a config option value.
+type OptionKind string
+
+const (
+	// OptionInt is an integer, boolean or color option
+	OptionInt OptionKind = "int"
+	// OptionFloat is a float option
+	OptionFloat OptionKind = "float"
+	// OptionString is a string option
+	OptionString OptionKind = "str"
+	// OptionVec2 is a 2D vector option
+	OptionVec2 OptionKind = "vec2"
+	// OptionCustom is a option with custom type like gradients or gaps. The
+	// value is the textual representation.
+	OptionCustom OptionKind = "custom"
+)
+
+// Option is a config option returned by getoption. Only the field of the
+// option's kind is set.
+type Option struct {
+	Name   string      `json:"option"`
+	Int    *int64      `json:"int,omitempty"`
+	Float  *float64    `json:"float,omitempty"`
+	Str    *string     `json:"str,omitempty"`
+	Vec2   *[2]float64 `json:"vec2,omitempty"`
+	Custom *string     `json:"custom,omitempty"`
+	// Set is true when the option is set in the config
+	Set bool `json:"set"`
+}
+
+// Kind returns the kind of the option value.
+func (o Option) Kind() OptionKind {
+	switch {
+	case o.Int != nil:
+		return OptionInt
+	case o.Float != nil:
+		return OptionFloat
+	case o.Str != nil:
+		return OptionString
+	case o.Vec2 != nil:
+		return OptionVec2
+	case o.Custom != nil:
+		return OptionCustom
+	default:
+		return ""
+	}
+}
+
+// Value returns the option value as any.
+func (o Option) Value() any {
+	switch o.Kind() {
+	case OptionInt:
+		return *o.Int
+	case OptionFloat:
+		return *o.Float
+	case OptionString:
+		return *o.Str
+	case OptionVec2:
+		return *o.Vec2
+	case OptionCustom:
+		return *o.Custom
+	default:
+		return nil
+	}
+}
+
+// Devices is the list of input devices known to hyprland.
+type Devices struct {
+	Mice      []Mouse       `json:"mice"`
+	Keyboards []Keyboard    `json:"keyboards"`
+	Tablets   []Tablet      `json:"tablets"`
+	Touch     []TouchDevice `json:"touch"`
+	Switches  []Switch      `json:"switches"`
+}
+
+// Mouse is a mouse or touchpad.
+type Mouse struct {
+	Address      string  `json:"address"`
+	Name         string  `json:"name"`
+	DefaultSpeed float64 `json:"defaultSpeed"`
+}
+
+// Keyboard is a keyboard and its keymap settings.
+type Keyboard struct {
+	Address      string `json:"address"`
+	Name         string `json:"name"`
+	Rules        string `json:"rules"`
+	Model        string `json:"model"`
+	Layout       string `json:"layout"`
+	Variant      string `json:"variant"`
+	Options      string `json:"options"`
+	ActiveKeymap string `json:"active_keymap"`
+	CapsLock     bool   `json:"capsLock"`
+	NumLock      bool   `json:"numLock"`
+	Main         bool   `json:"main"`
+}
+
+// Tablet is a tablet, tablet pad or tablet tool. Type is empty for tablets.
+type Tablet struct {
+	Address   string       `json:"address"`
+	Name      string       `json:"name"`
+	Type      string       `json:"type"`
+	BelongsTo *TabletOwner `json:"belongsTo,omitempty"`
+}
+
+// TabletOwner is the tablet a pad or tool belongs to.
+type TabletOwner struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// TouchDevice is a touch screen.
+type TouchDevice struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// Switch is a switch device, e.g. a lid or tablet mode switch.
+type Switch struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// MainKeyboard returns the main keyboard, the one reported by activelayout
+// events.
+func (d Devices) MainKeyboard() (Keyboard, bool) {
+	for _, k := range d.Keyboards {
+		if k.Main {
+			return k, true
+		}
+	}
+	return Keyboard{}, false
+}
+
+// LayerLevel is the z-order level of a layer surface.
+type LayerLevel int
+
+const (
+	// LayerBackground is the lowest level, below windows
+	LayerBackground LayerLevel = 0
+	// LayerBottom is below windows, above background
+	LayerBottom LayerLevel = 1
+	// LayerTop is above windows
+	LayerTop LayerLevel = 2
+	// LayerOverlay is above everything, including fullscreen windows
+	LayerOverlay LayerLevel = 3
+)
+
+// Layers maps monitor names to layer surfaces on that monitor grouped by
+// level.
+type Layers map[string]map[LayerLevel][]Layer
+
+// Layer is a layer surface, e.g. a bar or wallpaper.
+type Layer struct {
+	Address   string `json:"address"`
+	X         int64  `json:"x"`
+	Y         int64  `json:"y"`
+	W         int64  `json:"w"`
+	H         int64  `json:"h"`
+	Namespace string `json:"namespace"`
+	PID       int64  `json:"pid"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *Layers) UnmarshalJSON(data []byte) error {
+	var raw map[string]struct {
+		Levels map[LayerLevel][]Layer `json:"levels"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal layers: %w", err)
+	}
+
+	*l = make(Layers, len(raw))
+	for mon, levels := range raw {
+		(*l)[mon] = levels.Levels
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (l Layers) MarshalJSON() ([]byte, error) {
+	type levels struct {
+		Levels map[LayerLevel][]Layer `json:"levels"`
+	}
+	raw := make(map[string]levels, len(l))
+	for mon, lv := range l {
+		raw[mon] = levels{Levels: lv}
+	}
+	return json.Marshal(raw)
+}
+
+// Find returns the first layer surface with the given namespace.
+func (l Layers) Find(namespace string) (Layer, bool) {
+	for _, levels := range l {
+		for _, layers := range levels {
+			for _, layer := range layers {
+				if layer.Namespace == namespace {
+					return layer, true
+				}
+			}
+		}
+	}
+	return Layer{}, false
+}
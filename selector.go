@@ -0,0 +1,212 @@
+package hyprland
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WindowSelector identifies a window in dispatchers and requests. The zero
+// value selects the active window where hyprland allows omitting the window.
+type WindowSelector struct {
+	// prefix is the selector kind without ':', e.g. "class"
+	prefix string
+	// value is the part after ':', empty for keyword selectors
+	value string
+}
+
+// Window selector prefixes understood by hyprland.
+const (
+	selAddress      = "address"
+	selClass        = "class"
+	selInitialClass = "initialclass"
+	selTitle        = "title"
+	selInitialTitle = "initialtitle"
+	selPID          = "pid"
+	selTag          = "tag"
+)
+
+var (
+	// WindowActive selects the focused window
+	WindowActive = WindowSelector{prefix: "activewindow"}
+	// WindowFloating selects the first floating window on the workspace
+	WindowFloating = WindowSelector{prefix: "floating"}
+	// WindowTiled selects the first tiled window on the workspace
+	WindowTiled = WindowSelector{prefix: "tiled"}
+)
+
+// WindowByAddress selects a window by its address. Addresses from events,
+// which does not have the 0x prefix, are accepted.
+func WindowByAddress(address string) WindowSelector {
+	if !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+	return WindowSelector{prefix: selAddress, value: address}
+}
+
+// WindowOf selects the given client by its address.
+func WindowOf(c Client) WindowSelector {
+	return WindowByAddress(c.Address)
+}
+
+// WindowByClass selects the first window whose class matches the regex
+// pattern. Use ExactMatch to match a literal class.
+func WindowByClass(pattern string) WindowSelector {
+	return WindowSelector{prefix: selClass, value: pattern}
+}
+
+// WindowByInitialClass selects the first window whose initial class matches
+// the regex pattern.
+func WindowByInitialClass(pattern string) WindowSelector {
+	return WindowSelector{prefix: selInitialClass, value: pattern}
+}
+
+// WindowByTitle selects the first window whose title matches the regex
+// pattern. Use ExactMatch to match a literal title.
+func WindowByTitle(pattern string) WindowSelector {
+	return WindowSelector{prefix: selTitle, value: pattern}
+}
+
+// WindowByInitialTitle selects the first window whose initial title matches
+// the regex pattern.
+func WindowByInitialTitle(pattern string) WindowSelector {
+	return WindowSelector{prefix: selInitialTitle, value: pattern}
+}
+
+// WindowByPID selects the first window of the process.
+func WindowByPID(pid int) WindowSelector {
+	return WindowSelector{prefix: selPID, value: strconv.Itoa(pid)}
+}
+
+// WindowByTag selects the first window with the tag.
+func WindowByTag(tag string) WindowSelector {
+	return WindowSelector{prefix: selTag, value: tag}
+}
+
+// ExactMatch returns a regex pattern that matches s literally.
+func ExactMatch(s string) string {
+	return "^(" + regexp.QuoteMeta(s) + ")$"
+}
+
+// ParseWindowSelector parses a selector in hyprland's syntax, e.g.
+// "class:^(kitty)$" or "activewindow". The empty string is the zero
+// WindowSelector.
+func ParseWindowSelector(s string) (WindowSelector, error) {
+	var w WindowSelector
+	switch s {
+	case "":
+		return w, nil
+	case WindowActive.prefix:
+		return WindowActive, nil
+	case WindowFloating.prefix:
+		return WindowFloating, nil
+	case WindowTiled.prefix:
+		return WindowTiled, nil
+	}
+
+	prefix, value, found := strings.Cut(s, ":")
+	if !found {
+		return w, fmt.Errorf("invalid window selector: %q", s)
+	}
+	w = WindowSelector{prefix: prefix, value: value}
+	return w, w.Validate()
+}
+
+// IsZero returns if w is the zero WindowSelector.
+func (w WindowSelector) IsZero() bool {
+	return w == WindowSelector{}
+}
+
+// Validate checks that the selector can be sent to hyprland.
+func (w WindowSelector) Validate() error {
+	switch w.prefix {
+	case "", WindowActive.prefix, WindowFloating.prefix, WindowTiled.prefix:
+		if w.value != "" {
+			return fmt.Errorf("%s selector does not take a value", w.prefix)
+		}
+		return nil
+	case selAddress:
+		hex, ok := strings.CutPrefix(w.value, "0x")
+		if _, err := strconv.ParseUint(hex, 16, 64); !ok || err != nil {
+			return fmt.Errorf("invalid window address: %q", w.value)
+		}
+		return nil
+	case selPID:
+		if pid, err := strconv.Atoi(w.value); err != nil || pid <= 0 {
+			return fmt.Errorf("invalid pid: %q", w.value)
+		}
+		return nil
+	case selTag:
+		if w.value == "" || strings.ContainsAny(w.value, " ,;\n") {
+			return fmt.Errorf("invalid tag: %q", w.value)
+		}
+		return nil
+	case selClass, selInitialClass, selTitle, selInitialTitle:
+		if w.value == "" {
+			return fmt.Errorf("%s selector requires a pattern", w.prefix)
+		}
+		if _, err := regexp.Compile(w.value); err != nil {
+			return fmt.Errorf("invalid %s pattern: %w", w.prefix, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown window selector: %q", w.prefix)
+	}
+}
+
+// String returns the selector in hyprland's syntax. Characters that hyprland
+// uses to split arguments are escaped in regex patterns.
+func (w WindowSelector) String() string {
+	switch w.prefix {
+	case "":
+		return ""
+	case WindowActive.prefix, WindowFloating.prefix, WindowTiled.prefix:
+		return w.prefix
+	case selClass, selInitialClass, selTitle, selInitialTitle:
+		return w.prefix + ":" + escapePattern(w.value)
+	default:
+		return w.prefix + ":" + w.value
+	}
+}
+
+// arg validates w and returns it as a dispatcher argument.
+func (w WindowSelector) arg() (string, error) {
+	if err := w.Validate(); err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
+
+// escapePattern replaces ' ', ',' and ';' in a regex pattern with hex escapes
+// so the pattern survives hyprland's argument and batch splitting. Hyprland
+// uses RE2, the same syntax as regexp.
+func escapePattern(pattern string) string {
+	if !strings.ContainsAny(pattern, " ,;") {
+		return pattern
+	}
+
+	var b strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			if r == ' ' || r == ',' || r == ';' {
+				// '\;' is a literal ';', replace the whole escape sequence
+				fmt.Fprintf(&b, "x%02x", r)
+				continue
+			}
+		case r == '\\':
+			escaped = true
+		case r == ' ' || r == ',' || r == ';':
+			fmt.Fprintf(&b, `\x%02x`, r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var errNoWindow = errors.New("window selector is required")
@@ -0,0 +1,65 @@
+package hyprland
+
+import "testing"
+
+func TestWindowSelector(t *testing.T) {
+	tests := []struct {
+		name string
+		sel  WindowSelector
+		want string
+		err  bool
+	}{
+		{"Zero", WindowSelector{}, "", false},
+		{"Active", WindowActive, "activewindow", false},
+		{
+			"EventAddress",
+			WindowByAddress("64cea2525760"),
+			"address:0x64cea2525760",
+			false,
+		},
+		{"Client", WindowOf(Client{Address: "0x1"}), "address:0x1", false},
+		{"BadAddress", WindowByAddress("xyz"), "", true},
+		{"Class", WindowByClass("^(kitty)$"), "class:^(kitty)$", false},
+		{"Exact", WindowByTitle(ExactMatch("a.b c")), `title:^(a\.b\x20c)$`, false},
+		{"EscapedComma", WindowByTitle(`a\,b;c`), `title:a\x2cb\x3bc`, false},
+		{"BadRegex", WindowByClass("(kitty"), "", true},
+		{"PID", WindowByPID(42), "pid:42", false},
+		{"BadPID", WindowByPID(-1), "", true},
+		{"Tag", WindowByTag("work"), "tag:work", false},
+		{"BadTag", WindowByTag("a b"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sel.arg()
+			if (err != nil) != tt.err {
+				t.Fatalf("arg() error = %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("arg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWindowSelector(t *testing.T) {
+	for _, s := range []string{
+		"", "activewindow", "floating", "address:0xabc", "class:^(kitty)$",
+		"initialtitle:foo", "pid:10", "tag:work",
+	} {
+		w, err := ParseWindowSelector(s)
+		if err != nil {
+			t.Errorf("ParseWindowSelector(%q) failed: %v", s, err)
+			continue
+		}
+		if w.String() != s {
+			t.Errorf("ParseWindowSelector(%q).String() = %q", s, w.String())
+		}
+	}
+
+	for _, s := range []string{"kitty", "foo:bar", "pid:x", "class:("} {
+		if _, err := ParseWindowSelector(s); err == nil {
+			t.Errorf("ParseWindowSelector(%q) should fail", s)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// WindowProp is a window property that can be changed with setprop.
+type WindowProp string
+
+// Common window properties. See the hyprland wiki for the full list.
+const (
+	PropAlpha               WindowProp = "alpha"
+	PropAlphaInactive       WindowProp = "alphainactive"
+	PropAlphaFullscreen     WindowProp = "alphafullscreen"
+	PropRounding            WindowProp = "rounding"
+	PropBorderSize          WindowProp = "bordersize"
+	PropActiveBorderColor   WindowProp = "activebordercolor"
+	PropInactiveBorderColor WindowProp = "inactivebordercolor"
+	PropNoFocus             WindowProp = "nofocus"
+	PropNoAnim              WindowProp = "noanim"
+	PropNoBlur              WindowProp = "noblur"
+	PropNoBorder            WindowProp = "noborder"
+	PropNoDim               WindowProp = "nodim"
+	PropNoShadow            WindowProp = "noshadow"
+	PropOpaque              WindowProp = "opaque"
+	PropDimAround           WindowProp = "dimaround"
+	PropKeepAspectRatio     WindowProp = "keepaspectratio"
+	PropXray                WindowProp = "xray"
+	PropMaxSize             WindowProp = "maxsize"
+	PropMinSize             WindowProp = "minsize"
+)
+
+// SetProp sets a property of window. When lock is true the property is locked
+// and window rules can no longer change it.
+func (c *RequestClient) SetProp(
+	window WindowSelector,
+	prop WindowProp,
+	value string,
+	lock bool,
+) error {
+	return c.SetPropContext(context.Background(), window, prop, value, lock)
+}
+
+// SetPropContext is like SetProp but aborts the request when ctx is done.
+func (c *RequestClient) SetPropContext(
+	ctx context.Context,
+	window WindowSelector,
+	prop WindowProp,
+	value string,
+	lock bool,
+) error {
+	cmd, err := setPropCommand(window, prop, value, lock)
+	if err != nil {
+		return err
+	}
+	return c.commandContext(ctx, cmd)
+}
+
+// SetPropBool sets a boolean property like nofocus or opaque.
+func (c *RequestClient) SetPropBool(
+	window WindowSelector,
+	prop WindowProp,
+	value bool,
+	lock bool,
+) error {
+	return c.SetPropBoolContext(context.Background(), window, prop, value, lock)
+}
+
+// SetPropBoolContext is like SetPropBool but aborts the request when ctx is
+// done.
+func (c *RequestClient) SetPropBoolContext(
+	ctx context.Context,
+	window WindowSelector,
+	prop WindowProp,
+	value bool,
+	lock bool,
+) error {
+	return c.SetPropContext(ctx, window, prop, formatBool(value), lock)
+}
+
+// SetPropInt sets an integer property like rounding or bordersize.
+func (c *RequestClient) SetPropInt(
+	window WindowSelector,
+	prop WindowProp,
+	value int,
+	lock bool,
+) error {
+	return c.SetPropIntContext(context.Background(), window, prop, value, lock)
+}
+
+// SetPropIntContext is like SetPropInt but aborts the request when ctx is done.
+func (c *RequestClient) SetPropIntContext(
+	ctx context.Context,
+	window WindowSelector,
+	prop WindowProp,
+	value int,
+	lock bool,
+) error {
+	return c.SetPropContext(ctx, window, prop, strconv.Itoa(value), lock)
+}
+
+// SetPropFloat sets a float property like alpha.
+func (c *RequestClient) SetPropFloat(
+	window WindowSelector,
+	prop WindowProp,
+	value float64,
+	lock bool,
+) error {
+	return c.SetPropFloatContext(context.Background(), window, prop, value, lock)
+}
+
+// SetPropFloatContext is like SetPropFloat but aborts the request when ctx is
+// done.
+func (c *RequestClient) SetPropFloatContext(
+	ctx context.Context,
+	window WindowSelector,
+	prop WindowProp,
+	value float64,
+	lock bool,
+) error {
+	return c.SetPropContext(ctx, window, prop, formatFloat(value), lock)
+}
+
+// SetPropGradient sets a color property like activebordercolor.
+func (c *RequestClient) SetPropGradient(
+	window WindowSelector,
+	prop WindowProp,
+	value Gradient,
+	lock bool,
+) error {
+	return c.SetPropGradientContext(
+		context.Background(),
+		window,
+		prop,
+		value,
+		lock,
+	)
+}
+
+// SetPropGradientContext is like SetPropGradient but aborts the request when
+// ctx is done.
+func (c *RequestClient) SetPropGradientContext(
+	ctx context.Context,
+	window WindowSelector,
+	prop WindowProp,
+	value Gradient,
+	lock bool,
+) error {
+	return c.SetPropContext(ctx, window, prop, value.String(), lock)
+}
+
+// SetAlpha sets the opacity of window when it is focused.
+func (c *RequestClient) SetAlpha(
+	window WindowSelector,
+	alpha float64,
+	lock bool,
+) error {
+	return c.SetAlphaContext(context.Background(), window, alpha, lock)
+}
+
+// SetAlphaContext is like SetAlpha but aborts the request when ctx is done.
+func (c *RequestClient) SetAlphaContext(
+	ctx context.Context,
+	window WindowSelector,
+	alpha float64,
+	lock bool,
+) error {
+	return c.SetPropFloatContext(ctx, window, PropAlpha, alpha, lock)
+}
+
+// SetRounding sets the corner radius of window.
+func (c *RequestClient) SetRounding(
+	window WindowSelector,
+	radius int,
+	lock bool,
+) error {
+	return c.SetRoundingContext(context.Background(), window, radius, lock)
+}
+
+// SetRoundingContext is like SetRounding but aborts the request when ctx is
+// done.
+func (c *RequestClient) SetRoundingContext(
+	ctx context.Context,
+	window WindowSelector,
+	radius int,
+	lock bool,
+) error {
+	return c.SetPropIntContext(ctx, window, PropRounding, radius, lock)
+}
+
+// SetBorderColor sets the border color of window when it is focused.
+func (c *RequestClient) SetBorderColor(
+	window WindowSelector,
+	color Gradient,
+	lock bool,
+) error {
+	return c.SetBorderColorContext(context.Background(), window, color, lock)
+}
+
+// SetBorderColorContext is like SetBorderColor but aborts the request when ctx
+// is done.
+func (c *RequestClient) SetBorderColorContext(
+	ctx context.Context,
+	window WindowSelector,
+	color Gradient,
+	lock bool,
+) error {
+	return c.SetPropGradientContext(
+		ctx,
+		window,
+		PropActiveBorderColor,
+		color,
+		lock,
+	)
+}
+
+// SetNoFocus sets if window can be focused.
+func (c *RequestClient) SetNoFocus(
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetNoFocusContext(context.Background(), window, value, lock)
+}
+
+// SetNoFocusContext is like SetNoFocus but aborts the request when ctx is done.
+func (c *RequestClient) SetNoFocusContext(
+	ctx context.Context,
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetPropBoolContext(ctx, window, PropNoFocus, value, lock)
+}
+
+// SetNoAnim sets if window is animated.
+func (c *RequestClient) SetNoAnim(
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetNoAnimContext(context.Background(), window, value, lock)
+}
+
+// SetNoAnimContext is like SetNoAnim but aborts the request when ctx is done.
+func (c *RequestClient) SetNoAnimContext(
+	ctx context.Context,
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetPropBoolContext(ctx, window, PropNoAnim, value, lock)
+}
+
+// SetOpaque sets if window is forced to be opaque.
+func (c *RequestClient) SetOpaque(
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetOpaqueContext(context.Background(), window, value, lock)
+}
+
+// SetOpaqueContext is like SetOpaque but aborts the request when ctx is done.
+func (c *RequestClient) SetOpaqueContext(
+	ctx context.Context,
+	window WindowSelector,
+	value bool,
+	lock bool,
+) error {
+	return c.SetPropBoolContext(ctx, window, PropOpaque, value, lock)
+}
+
+func setPropCommand(
+	window WindowSelector,
+	prop WindowProp,
+	value string,
+	lock bool,
+) (string, error) {
+	if window.IsZero() {
+		return "", errNoWindow
+	}
+	w, err := window.arg()
+	if err != nil {
+		return "", err
+	}
+	if prop == "" || value == "" {
+		return "", errors.New("setprop requires a property and value")
+	}
+	cmd := "setprop " + w + " " + string(prop) + " " + value
+	if lock {
+		cmd += " lock"
+	}
+	return cmd, nil
+}
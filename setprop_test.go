@@ -0,0 +1,18 @@
+package hyprland
+
+import "testing"
+
+func TestSetPropCommand(t *testing.T) {
+	got, err := setPropCommand(WindowByAddress("0x1"), PropAlpha, "0.8", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "setprop address:0x1 alpha 0.8 lock"; got != want {
+		t.Errorf("setPropCommand() = %q, want %q", got, want)
+	}
+
+	_, err = setPropCommand(WindowSelector{}, PropNoFocus, "1", false)
+	if err == nil {
+		t.Error("setPropCommand() without window should fail")
+	}
+}
@@ -1,13 +1,5 @@
 package hyprland
 
-import (
-	"errors"
-	"fmt"
-	"os"
-	"os/user"
-	"path/filepath"
-)
-
 // SocketPath is the path of a hyprland socket
 type SocketPath string
 
@@ -22,19 +14,9 @@ func should[T any](v T, _ error) T {
 }
 
 func getSocket(name string) (SocketPath, error) {
-	his, ok := os.LookupEnv("HYPRLAND_INSTANCE_SIGNATURE")
-	if !ok {
-		return "", errors.New("HYPRLAND_INSTANCE_SIGNATURE env does not exist")
-	}
-
-	xdgRuntimeDir, ok := os.LookupEnv("XDG_RUNTIME_DIR")
-	if !ok {
-		u, err := user.Current()
-		if err != nil {
-			return "", fmt.Errorf("failed to get XDG_RUNTIME_DIR: %w", err)
-		}
-		xdgRuntimeDir = filepath.Join("/run/user", u.Uid)
+	inst, err := CurrentInstance()
+	if err != nil {
+		return "", err
 	}
-
-	return SocketPath(filepath.Join(xdgRuntimeDir, "hypr", his, name)), nil
+	return inst.socket(name)
 }
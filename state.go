@@ -0,0 +1,691 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReconcileInterval is the default time between two reconciliations of
+// a State running with Run.
+const DefaultReconcileInterval = time.Minute
+
+// Snapshot is a copy of the compositor state at one point in time. It is
+// never changed by State and is safe to keep.
+type Snapshot struct {
+	Monitors   Monitors
+	Workspaces Workspaces
+	Clients    Clients
+	// ActiveWindow is the address of the focused window, empty if none
+	ActiveWindow string
+	// FocusedMonitor is the name of the focused monitor
+	FocusedMonitor string
+}
+
+// Client returns the client with the given address.
+func (s Snapshot) Client(address string) (Client, bool) {
+	i := slices.IndexFunc(s.Clients, func(c Client) bool {
+		return c.Address == address
+	})
+	if i < 0 {
+		return Client{}, false
+	}
+	return s.Clients[i], true
+}
+
+// Workspace returns the workspace with the given id.
+func (s Snapshot) Workspace(id int64) (Workspace, bool) {
+	i := slices.IndexFunc(s.Workspaces, func(w Workspace) bool {
+		return w.ID == id
+	})
+	if i < 0 {
+		return Workspace{}, false
+	}
+	return s.Workspaces[i], true
+}
+
+// Monitor returns the monitor with the given name.
+func (s Snapshot) Monitor(name string) (Monitor, bool) {
+	i := slices.IndexFunc(s.Monitors, func(m Monitor) bool {
+		return m.Name == name
+	})
+	if i < 0 {
+		return Monitor{}, false
+	}
+	return s.Monitors[i], true
+}
+
+// clone returns a deep copy of s, sharing no slice with it.
+func (s Snapshot) clone() Snapshot {
+	s.Monitors = slices.Clone(s.Monitors)
+	for i := range s.Monitors {
+		m := &s.Monitors[i]
+		m.Reserved = slices.Clone(m.Reserved)
+		m.AvailableModes = slices.Clone(m.AvailableModes)
+	}
+	s.Workspaces = slices.Clone(s.Workspaces)
+	s.Clients = slices.Clone(s.Clients)
+	for i := range s.Clients {
+		c := &s.Clients[i]
+		c.At = slices.Clone(c.At)
+		c.Size = slices.Clone(c.Size)
+		c.Grouped = slices.Clone(c.Grouped)
+		c.Tags = slices.Clone(c.Tags)
+	}
+	return s
+}
+
+// OnChangeFunc is called after the State changed. event is the event that
+// caused the change, or empty after a reconciliation.
+type OnChangeFunc func(snapshot Snapshot, event Event)
+
+// State keeps an in-memory model of monitors, workspaces and clients current
+// by applying hyprland events to it. It is seeded from the request socket and
+// reconciled with it periodically to repair anything the events do not carry.
+//
+// Use Run to keep the state in sync with its own EventListener, or call
+// Reconcile once and register Handle on an existing listener with
+// OnAllEvents. State is safe for concurrent use.
+type State struct {
+	// Client is used to seed and reconcile the state
+	Client *RequestClient
+	// Interval is the time between reconciliations in Run. Zero disables
+	// periodic reconciliation.
+	Interval time.Duration
+
+	// reconcileMu serializes reconciliations
+	reconcileMu sync.Mutex
+
+	mu       sync.RWMutex
+	snapshot Snapshot
+	onChange []OnChangeFunc
+	// inFlight is the number of requests waiting for their reply, replay
+	// collects the events applied meanwhile, see record
+	inFlight int
+	replay   []EventValue
+	// refresh queues the addresses of windows whose fullscreen mode is read
+	// by the worker of Run, nil when Run is not running. stopped is closed
+	// when Run stops.
+	refresh chan string
+	stopped <-chan struct{}
+}
+
+// NewState creates an empty State that reconciles with c every
+// DefaultReconcileInterval.
+func NewState(c *RequestClient) *State {
+	return &State{Client: c, Interval: DefaultReconcileInterval}
+}
+
+// Snapshot returns a copy of the current state.
+func (s *State) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot.clone()
+}
+
+// OnChange adds fn to the functions called after every change. fn is called
+// from the goroutine that applied the change and must not block.
+func (s *State) OnChange(fn OnChangeFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Reconcile replaces the state with the current monitors, workspaces and
+// clients read from the request socket in a single batch request. Events
+// handled while the request is in flight are applied again on top of the
+// new state, so they are not lost.
+func (s *State) Reconcile(ctx context.Context) error {
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	s.mu.Lock()
+	start := s.record()
+	s.mu.Unlock()
+
+	var snap Snapshot
+	var active Client
+	_, err := s.Client.NewBatch().
+		Query("monitors", &snap.Monitors).
+		Query("workspaces", &snap.Workspaces).
+		Query("clients", &snap.Clients).
+		Query("activewindow", &active).
+		SendContext(ctx)
+
+	s.mu.Lock()
+	replay := s.recorded(start)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	snap.ActiveWindow = active.Address
+	for _, m := range snap.Monitors {
+		if m.Focused {
+			snap.FocusedMonitor = m.Name
+		}
+	}
+	s.snapshot = snap
+	for _, value := range replay {
+		s.apply(value)
+	}
+	s.mu.Unlock()
+
+	s.changed("")
+	return nil
+}
+
+// Run keeps the state in sync by listening for events and reconciling every
+// Interval until ctx is done or an error occurs. It listens to the instance of
+// Client and reconnects when the connection drops, reconciling after every
+// reconnect. The state is seeded after the listener connected, so no event is
+// missed between the two. Reconciliations that fail because hyprland is not
+// reachable are left to the next reconnect or Interval.
+func (s *State) Run(ctx context.Context) error {
+	inst, err := s.Client.Instance()
+	if err != nil {
+		return err
+	}
+	l, err := NewEventListenerFor(inst)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	reconcile := func(ctx context.Context) {
+		err := s.Reconcile(ctx)
+		if err != nil && !errors.Is(err, ErrSocketUnavailable) {
+			cancel(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	if s.Interval > 0 {
+		wg.Go(func() {
+			ticker := time.NewTicker(s.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					reconcile(ctx)
+				}
+			}
+		})
+	}
+
+	// fullscreen modes are read one at a time, in the order of the events
+	refresh := make(chan string, 16)
+	s.mu.Lock()
+	s.refresh, s.stopped = refresh, ctx.Done()
+	s.mu.Unlock()
+	wg.Go(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case address := <-refresh:
+				s.refreshFullscreen(ctx, address)
+			}
+		}
+	})
+
+	l.Reconnect = &ReconnectPolicy{Resync: reconcile}
+	// Handle ignores malformed events, they must not stop the state
+	l.ErrorPolicy = ErrorSkip
+	l.OnAllEvents(s.Handle)
+	seeded := false
+	l.OnConnected(func(ctx *EventContext, _ SocketPath) {
+		// Resync only runs after reconnecting, seed on the first connection
+		if !seeded {
+			seeded = true
+			reconcile(ctx)
+		}
+	})
+	err = l.listen(ctx, l.processEvent)
+
+	// no OnChange function is called once Run returned
+	cancel(nil)
+	wg.Wait()
+	s.mu.Lock()
+	s.refresh, s.stopped = nil, nil
+	s.mu.Unlock()
+
+	if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+		return cause
+	}
+	return err
+}
+
+// Handle applies an event to the state. It has the signature of
+// OnAllEventsFunc, so it can be registered on any EventListener. Events that
+// do not change the state and malformed events are ignored. The mode of a
+// window entering fullscreen is read from the request socket, as the event
+// does not carry it. While Run is running it is read by a worker of Run
+// without blocking, otherwise Handle reads it before returning.
+func (s *State) Handle(ctx *EventContext) {
+	value, err := decodeEvent(ctx)
+	if err != nil {
+		return
+	}
+	if v, ok := value.(FullscreenEvent); ok && v.Fullscreen {
+		// the event is about the window that is active now, the reply is
+		// only applied if it is about the same window
+		s.mu.RLock()
+		address := s.snapshot.ActiveWindow
+		refresh, stopped := s.refresh, s.stopped
+		s.mu.RUnlock()
+		if refresh == nil {
+			s.refreshFullscreen(ctx, address)
+			return
+		}
+		select {
+		case refresh <- address:
+		case <-stopped:
+		}
+		return
+	}
+	s.update(value)
+}
+
+// update applies value and calls the OnChange functions if it changed the
+// state.
+func (s *State) update(value EventValue) {
+	s.mu.Lock()
+	if s.inFlight > 0 {
+		s.replay = append(s.replay, value)
+	}
+	changed := s.apply(value)
+	s.mu.Unlock()
+
+	if changed {
+		s.changed(value.EventName())
+	}
+}
+
+// record starts recording the events applied while a request waits for its
+// reply and returns the start of the recording. Pass it to recorded once the
+// reply arrived. s.mu must be held.
+func (s *State) record() int {
+	s.inFlight++
+	return len(s.replay)
+}
+
+// recorded returns the events applied since start and ends the recording.
+// Applying them again on top of the reply catches the reply up with the
+// events. s.mu must be held.
+func (s *State) recorded(start int) []EventValue {
+	replay := slices.Clone(s.replay[start:])
+	s.inFlight--
+	if s.inFlight == 0 {
+		s.replay = nil
+	}
+	return replay
+}
+
+// changed calls the OnChange functions with a fresh snapshot.
+func (s *State) changed(event Event) {
+	s.mu.RLock()
+	fns := slices.Clone(s.onChange)
+	s.mu.RUnlock()
+
+	if len(fns) == 0 {
+		return
+	}
+	snap := s.Snapshot()
+	for _, fn := range fns {
+		fn(snap, event)
+	}
+}
+
+// fullscreenMode is the fullscreen mode of a window read from the request
+// socket. The fullscreen event only reports that a window entered fullscreen,
+// not the mode.
+type fullscreenMode struct {
+	// Address is the address of the window without 0x
+	Address string
+	// Mode is the fullscreen mode as reported in Client.Fullscreen
+	Mode int64
+}
+
+// EventName implements EventValue.
+func (fullscreenMode) EventName() Event {
+	return EventFullscreen
+}
+
+// refreshFullscreen reads the fullscreen mode of the window with address, which
+// must be the active window, and applies it. Events applied while waiting for
+// the reply are applied again on top of it. If the request fails or another
+// window became active meanwhile, the mode is left to the next
+// reconciliation.
+func (s *State) refreshFullscreen(ctx context.Context, address string) {
+	if address == "" {
+		return
+	}
+
+	s.mu.Lock()
+	start := s.record()
+	s.mu.Unlock()
+
+	active, err := s.Client.GetActiveWindowContext(ctx)
+
+	s.mu.Lock()
+	replay := s.recorded(start)
+	if err != nil || active.Address != address {
+		s.mu.Unlock()
+		return
+	}
+	mode := fullscreenMode{
+		Address: strings.TrimPrefix(active.Address, "0x"),
+		Mode:    active.Fullscreen,
+	}
+	if s.inFlight > 0 {
+		s.replay = append(s.replay, mode)
+	}
+	changed := s.apply(mode)
+	for _, value := range replay {
+		changed = s.apply(value) || changed
+	}
+	s.mu.Unlock()
+
+	if changed {
+		s.changed(EventFullscreen)
+	}
+}
+
+// apply applies an event and reports if the state changed. s.mu must be held.
+func (s *State) apply(value EventValue) bool {
+	snap := &s.snapshot
+	switch v := value.(type) {
+	case OpenWindowEvent:
+		if s.client(v.Address) != nil {
+			return false
+		}
+		ws := s.workspaceByName(v.Workspace)
+		c := Client{
+			Address:      "0x" + v.Address,
+			Mapped:       true,
+			Workspace:    SimpleWorkspace{Name: v.Workspace},
+			Class:        v.Class,
+			Title:        v.Title,
+			InitialClass: v.Class,
+			InitialTitle: v.Title,
+		}
+		if ws != nil {
+			c.Workspace.ID = ws.ID
+			c.Monitor = ws.MonitorID
+			ws.Windows++
+		}
+		snap.Clients = append(snap.Clients, c)
+		return true
+	case CloseWindowEvent:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		if ws := s.workspace(c.Workspace.ID); ws != nil {
+			ws.Windows--
+			ws.Hasfullscreen = ws.Hasfullscreen && c.Fullscreen == 0
+		}
+		if snap.ActiveWindow == c.Address {
+			snap.ActiveWindow = ""
+		}
+		addr := c.Address
+		snap.Clients = slices.DeleteFunc(snap.Clients, func(c Client) bool {
+			return c.Address == addr
+		})
+		return true
+	case MoveWindowV2Event:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		id := int64(v.WorkspaceID)
+		if ws := s.workspace(c.Workspace.ID); ws != nil {
+			ws.Windows--
+			ws.Hasfullscreen = ws.Hasfullscreen && c.Fullscreen == 0
+		}
+		c.Workspace = SimpleWorkspace{ID: id, Name: v.Workspace}
+		if ws := s.workspace(id); ws != nil {
+			c.Monitor = ws.MonitorID
+			ws.Windows++
+		}
+		return true
+	case ActiveWindowV2Event:
+		addr := ""
+		if v.Address != "" {
+			addr = "0x" + v.Address
+		}
+		snap.ActiveWindow = addr
+		return true
+	case WindowTitleV2Event:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		c.Title = v.Title
+		return true
+	case ChangeFloatingModeEvent:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		c.Floating = v.Floating
+		return true
+	case PinEvent:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		c.Pinned = v.Pinned
+		return true
+	case FullscreenEvent:
+		if v.Fullscreen {
+			return false
+		}
+		return s.setFullscreen(strings.TrimPrefix(snap.ActiveWindow, "0x"), 0)
+	case fullscreenMode:
+		return s.setFullscreen(v.Address, v.Mode)
+	case ToggleGroupEvent:
+		var group []string
+		if v.State {
+			for _, addr := range v.Addresses {
+				group = append(group, "0x"+addr)
+			}
+		}
+		for _, addr := range v.Addresses {
+			if c := s.client(addr); c != nil {
+				c.Grouped = group
+			}
+		}
+		return true
+	case MoveOutOfGroupEvent:
+		c := s.client(v.Address)
+		if c == nil {
+			return false
+		}
+		rest := slices.DeleteFunc(slices.Clone(c.Grouped), func(a string) bool {
+			return a == c.Address
+		})
+		for _, addr := range rest {
+			if m := s.client(strings.TrimPrefix(addr, "0x")); m != nil {
+				m.Grouped = rest
+			}
+		}
+		c.Grouped = nil
+		return true
+	case CreateWorkspaceV2Event:
+		id := int64(v.ID)
+		if s.workspace(id) != nil {
+			return false
+		}
+		ws := Workspace{ID: id, Name: v.Name, Lastwindow: "0x0"}
+		if m := s.monitor(snap.FocusedMonitor); m != nil {
+			ws.Monitor, ws.MonitorID = m.Name, m.ID
+		}
+		snap.Workspaces = append(snap.Workspaces, ws)
+		slices.SortFunc(snap.Workspaces, func(a, b Workspace) int {
+			return int(a.ID - b.ID)
+		})
+		return true
+	case DestroyWorkspaceV2Event:
+		id := int64(v.ID)
+		if s.workspace(id) == nil {
+			return false
+		}
+		snap.Workspaces = slices.DeleteFunc(
+			snap.Workspaces,
+			func(w Workspace) bool { return w.ID == id },
+		)
+		return true
+	case RenameWorkspaceEvent:
+		id := int64(v.ID)
+		ws := s.workspace(id)
+		if ws == nil {
+			return false
+		}
+		ws.Name = v.NewName
+		s.renameWorkspace(id, v.NewName)
+		return true
+	case MoveWorkspaceV2Event:
+		id := int64(v.ID)
+		ws, m := s.workspace(id), s.monitor(v.Monitor)
+		if ws == nil || m == nil {
+			return false
+		}
+		ws.Monitor, ws.MonitorID = m.Name, m.ID
+		for i := range snap.Clients {
+			if snap.Clients[i].Workspace.ID == id {
+				snap.Clients[i].Monitor = m.ID
+			}
+		}
+		return true
+	case WorkspaceV2Event:
+		m := s.monitor(snap.FocusedMonitor)
+		if m == nil {
+			return false
+		}
+		m.ActiveWorkspace = SimpleWorkspace{ID: int64(v.ID), Name: v.Name}
+		return true
+	case FocusedMonV2Event:
+		m := s.monitor(v.Monitor)
+		if m == nil {
+			return false
+		}
+		snap.FocusedMonitor = v.Monitor
+		for i := range snap.Monitors {
+			snap.Monitors[i].Focused = snap.Monitors[i].Name == v.Monitor
+		}
+		id := int64(v.WorkspaceID)
+		m.ActiveWorkspace.ID = id
+		if ws := s.workspace(id); ws != nil {
+			m.ActiveWorkspace.Name = ws.Name
+		}
+		return true
+	case ActiveSpecialV2Event:
+		m := s.monitor(v.Monitor)
+		if m == nil {
+			return false
+		}
+		m.SpecialWorkspace = SimpleWorkspace{ID: int64(v.ID), Name: v.Name}
+		return true
+	case MonitorAddedV2Event:
+		if s.monitor(v.Name) != nil {
+			return false
+		}
+		// the remaining fields are filled by the next reconciliation
+		snap.Monitors = append(snap.Monitors, Monitor{
+			ID:          int64(v.ID),
+			Name:        v.Name,
+			Description: v.Description,
+		})
+		return true
+	case MonitorRemovedV2Event:
+		if s.monitor(v.Name) == nil {
+			return false
+		}
+		snap.Monitors = slices.DeleteFunc(snap.Monitors, func(m Monitor) bool {
+			return m.Name == v.Name
+		})
+		if snap.FocusedMonitor == v.Name {
+			snap.FocusedMonitor = ""
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// setFullscreen sets the fullscreen mode of the client with address and
+// updates its workspace. s.mu must be held.
+func (s *State) setFullscreen(address string, mode int64) bool {
+	c := s.client(address)
+	if c == nil {
+		return false
+	}
+	c.Fullscreen = mode
+	if ws := s.workspace(c.Workspace.ID); ws != nil {
+		ws.Hasfullscreen = mode != 0
+	}
+	return true
+}
+
+// client returns the client with address as sent in events, without 0x.
+func (s *State) client(address string) *Client {
+	for i := range s.snapshot.Clients {
+		if s.snapshot.Clients[i].Address == "0x"+address {
+			return &s.snapshot.Clients[i]
+		}
+	}
+	return nil
+}
+
+func (s *State) workspace(id int64) *Workspace {
+	for i := range s.snapshot.Workspaces {
+		if s.snapshot.Workspaces[i].ID == id {
+			return &s.snapshot.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+func (s *State) workspaceByName(name string) *Workspace {
+	for i := range s.snapshot.Workspaces {
+		if s.snapshot.Workspaces[i].Name == name {
+			return &s.snapshot.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+func (s *State) monitor(name string) *Monitor {
+	for i := range s.snapshot.Monitors {
+		if s.snapshot.Monitors[i].Name == name {
+			return &s.snapshot.Monitors[i]
+		}
+	}
+	return nil
+}
+
+// renameWorkspace updates the workspace name cached in clients and monitors.
+func (s *State) renameWorkspace(id int64, name string) {
+	for i := range s.snapshot.Clients {
+		if s.snapshot.Clients[i].Workspace.ID == id {
+			s.snapshot.Clients[i].Workspace.Name = name
+		}
+	}
+	for i := range s.snapshot.Monitors {
+		m := &s.snapshot.Monitors[i]
+		if m.ActiveWorkspace.ID == id {
+			m.ActiveWorkspace.Name = name
+		}
+		if m.SpecialWorkspace.ID == id {
+			m.SpecialWorkspace.Name = name
+		}
+	}
+}
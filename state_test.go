@@ -0,0 +1,417 @@
+package hyprland
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+// summary is the part of a snapshot that events are expected to keep exact.
+func summary(s Snapshot) []string {
+	var lines []string
+	for _, m := range s.Monitors {
+		lines = append(lines, fmt.Sprintf(
+			"monitor %s active=%d special=%q focused=%v",
+			m.Name,
+			m.ActiveWorkspace.ID,
+			m.SpecialWorkspace.Name,
+			m.Focused,
+		))
+	}
+	for _, w := range s.Workspaces {
+		lines = append(lines, fmt.Sprintf(
+			"workspace %d %s on %s windows=%d fullscreen=%v",
+			w.ID,
+			w.Name,
+			w.Monitor,
+			w.Windows,
+			w.Hasfullscreen,
+		))
+	}
+	for _, c := range s.Clients {
+		lines = append(lines, fmt.Sprintf(
+			"client %s %s %q ws=%d floating=%v fullscreen=%d grouped=%v",
+			c.Address,
+			c.Class,
+			c.Title,
+			c.Workspace.ID,
+			c.Floating,
+			c.Fullscreen,
+			c.Grouped,
+		))
+	}
+	return append(lines, "active "+s.ActiveWindow, "focused "+s.FocusedMonitor)
+}
+
+func TestState(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+	comp.AddMonitor("HDMI-A-1", 1920, 1080)
+	_, err := comp.OpenWindow(hyprlandtest.Window{Class: "kitty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := NewRequestClient()
+	state := NewState(c)
+	state.Interval = 0
+	events := make(chan Event, 100)
+	state.OnChange(func(_ Snapshot, event Event) {
+		events <- event
+	})
+
+	// wait blocks until the state applied event
+	wait := func(event Event) {
+		t.Helper()
+		for {
+			select {
+			case got := <-events:
+				if got == event {
+					return
+				}
+			case <-ctx.Done():
+				t.Fatalf("state did not apply %s", event)
+			}
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- state.Run(ctx) }()
+	// the state is seeded after the listener connected
+	wait("")
+	if snap := state.Snapshot(); len(snap.Clients) != 1 {
+		t.Fatalf("seeded clients = %+v, want kitty", snap.Clients)
+	}
+
+	firefox, err := comp.OpenWindow(hyprlandtest.Window{
+		Class: "firefox",
+		Title: "a, b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(EventActiveWindowV2)
+	if got, ok := state.Snapshot().Client(firefox); !ok || got.Title != "a, b" {
+		t.Errorf("Client(%s) = %+v, want title with comma", firefox, got)
+	}
+
+	d := c.Dispatch()
+	fullscreen := func(mode FullscreenMode) func() error {
+		return func() error { return d.Fullscreen(mode) }
+	}
+	steps := []struct {
+		name string
+		call func() error
+		last Event
+	}{
+		{
+			"MoveToWorkspaceSilent",
+			func() error {
+				return d.MoveToWorkspaceSilent(
+					WorkspaceByID(3),
+					WindowByAddress(firefox),
+				)
+			},
+			EventActiveWindowV2,
+		},
+		{
+			"FocusMonitor",
+			func() error { return d.FocusMonitor("HDMI-A-1") },
+			EventActiveWindowV2,
+		},
+		{
+			"Workspace",
+			func() error { return d.Workspace(WorkspaceByID(3)) },
+			EventActiveWindowV2,
+		},
+		{
+			"ToggleFloating",
+			func() error { return d.ToggleFloating(WindowSelector{}) },
+			EventChangeFloatingMode,
+		},
+		{
+			"ToggleSpecialWorkspace",
+			func() error { return d.ToggleSpecialWorkspace("") },
+			EventActiveWindowV2,
+		},
+		{
+			"CloseSpecialWorkspace",
+			func() error { return d.ToggleSpecialWorkspace("") },
+			EventDestroyWorkspaceV2,
+		},
+		{"Maximize", fullscreen(FullscreenMaximize), EventFullscreen},
+		{"Unmaximize", fullscreen(FullscreenMaximize), EventFullscreen},
+		{"Fullscreen", fullscreen(FullscreenFull), EventFullscreen},
+		{"ToggleGroup", d.ToggleGroup, EventToggleGroup},
+		{"KillActive", d.KillActive, EventActiveWindowV2},
+	}
+
+	for _, step := range steps {
+		if err := step.call(); err != nil {
+			t.Fatalf("%s() failed: %v", step.name, err)
+		}
+		wait(step.last)
+
+		got := summary(state.Snapshot())
+		fresh := NewState(c)
+		if err := fresh.Reconcile(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if want := summary(fresh.Snapshot()); !slices.Equal(got, want) {
+			t.Errorf("after %s state =\n%q\nwant\n%q", step.name, got, want)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestStateReconcileReplay(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.Handle("j/monitors", `[{"id":0,"name":"DP-1","focused":true}]`)
+	s.Handle("j/workspaces", `[{"id":1,"name":"1","monitor":"DP-1"}]`)
+	s.Handle("j/clients", "[]")
+
+	state := NewState(NewRequestClient())
+	// the window opens after the clients were read, while the reply of the
+	// reconciliation is still in flight
+	s.HandleFunc("j/activewindow", func(string) string {
+		state.Handle(&EventContext{
+			Context: context.Background(),
+			Event:   EventOpenWindow,
+			RawData: "5a1f,1,kitty,shell",
+		})
+		return "{}"
+	})
+
+	if err := state.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	snap := state.Snapshot()
+	if c, ok := snap.Client("0x5a1f"); !ok || c.Title != "shell" {
+		t.Errorf("Client(0x5a1f) = %+v, want the window opened meanwhile", c)
+	}
+	if ws, _ := snap.Workspace(1); ws.Windows != 1 {
+		t.Errorf("workspace 1 windows = %d, want 1", ws.Windows)
+	}
+}
+
+func TestStateInstance(t *testing.T) {
+	// the env points to env, the client to other
+	env := hyprlandtest.NewServer(t)
+	other := startInstance(t)
+
+	comp := hyprlandtest.NewCompositor(other)
+	comp.AddMonitor("DP-1", 1920, 1080)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state := NewState(&RequestClient{Socket: SocketPath(other.RequestSocket())})
+	state.Interval = 0
+	events := make(chan Event, 100)
+	state.OnChange(func(_ Snapshot, event Event) {
+		events <- event
+	})
+	done := make(chan error, 1)
+	go func() { done <- state.Run(ctx) }()
+	if err := other.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := comp.OpenWindow(hyprlandtest.Window{Class: "kitty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, ok := state.Snapshot().Client(addr); ok {
+			break
+		}
+		select {
+		case <-events:
+		case <-ctx.Done():
+			t.Fatalf("state did not apply openwindow of %s", addr)
+		}
+	}
+	if n := env.Listeners(); n != 0 {
+		t.Errorf("instance from env has %d listeners, want 0", n)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestStateReconnect(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	comp := hyprlandtest.NewCompositor(s)
+	comp.AddMonitor("DP-1", 1920, 1080)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state := NewState(NewRequestClient())
+	state.Interval = 0
+	changes := make(chan Event, 10)
+	state.OnChange(func(_ Snapshot, event Event) {
+		changes <- event
+	})
+	done := make(chan error, 1)
+	go func() { done <- state.Run(ctx) }()
+	receive(ctx, t, changes, 1)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the state is reconciled again after reconnecting
+	s.DisconnectListeners()
+	if got := receive(ctx, t, changes, 1)[0]; got != "" {
+		t.Errorf("change after reconnecting = %q, want a reconciliation", got)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestStateSnapshotCopy(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.Handle("j/monitors", `[{
+		"name": "DP-1",
+		"focused": true,
+		"reserved": [0, 30, 0, 0],
+		"availableModes": ["1920x1080@60.00Hz"]
+	}]`)
+	s.Handle("j/workspaces", `[{"id": 1, "name": "1", "monitor": "DP-1"}]`)
+	s.Handle("j/clients", `[{
+		"address": "0x5a1f",
+		"at": [10, 20],
+		"size": [800, 600],
+		"grouped": ["0x5a1f"],
+		"tags": ["term"]
+	}]`)
+	s.Handle("j/activewindow", "{}")
+
+	state := NewState(NewRequestClient())
+	if err := state.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// changing a snapshot must not change the state
+	snap := state.Snapshot()
+	snap.Monitors[0].Reserved[1] = 0
+	snap.Monitors[0].AvailableModes[0] = ""
+	snap.Workspaces[0].Name = ""
+	c := &snap.Clients[0]
+	c.At[0], c.Size[0], c.Grouped[0], c.Tags[0] = 0, 0, "", ""
+
+	got := state.Snapshot()
+	m := got.Monitors[0]
+	if m.Reserved[1] != 30 || m.AvailableModes[0] != "1920x1080@60.00Hz" {
+		t.Errorf("monitor changed through a snapshot: %+v", m)
+	}
+	if got.Workspaces[0].Name != "1" {
+		t.Errorf("workspace changed through a snapshot: %+v", got.Workspaces[0])
+	}
+	c = &got.Clients[0]
+	if c.At[0] != 10 || c.Size[0] != 800 || c.Grouped[0] != "0x5a1f" ||
+		c.Tags[0] != "term" {
+		t.Errorf("client changed through a snapshot: %+v", *c)
+	}
+}
+
+func TestStateFullscreen(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.Handle("j/monitors", `[{"name": "DP-1", "focused": true}]`)
+	s.Handle("j/workspaces", `[{"id": 1, "name": "1", "monitor": "DP-1"}]`)
+	s.Handle("j/clients", `[{"address": "0x5a1f", "workspace": {"id": 1}}]`)
+	s.HandleFunc("j/activewindow", func(string) string {
+		return `{"address": "0x5a1f"}`
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state := NewState(NewRequestClient())
+	state.Interval = 0
+	changes := make(chan Event, 10)
+	state.OnChange(func(_ Snapshot, event Event) {
+		changes <- event
+	})
+	done := make(chan error, 1)
+	go func() { done <- state.Run(ctx) }()
+	if got := receive(ctx, t, changes, 1)[0]; got != "" {
+		t.Fatalf("first change = %q, want the seed", got)
+	}
+
+	fullscreen := func() int64 {
+		c, _ := state.Snapshot().Client("0x5a1f")
+		return c.Fullscreen
+	}
+	// every lookup blocks until its reply is released
+	requested, release := make(chan struct{}), make(chan string)
+	s.HandleFunc("j/activewindow", func(string) string {
+		requested <- struct{}{}
+		return <-release
+	})
+
+	// the mode is read without blocking other events
+	s.Emit("fullscreen", "1")
+	receive(ctx, t, requested, 1)
+	s.Emit("windowtitlev2", "5a1f,maximized")
+	if got := receive(ctx, t, changes, 1)[0]; got != EventWindowTitleV2 {
+		t.Fatalf("change = %q while reading the fullscreen mode", got)
+	}
+	release <- `{"address": "0x5a1f", "fullscreen": 1}`
+	if got := receive(ctx, t, changes, 1)[0]; got != EventFullscreen {
+		t.Fatalf("change = %q, want %q", got, EventFullscreen)
+	}
+	if got := fullscreen(); got != 1 {
+		t.Errorf("fullscreen = %d, want 1 for maximized", got)
+	}
+
+	s.Emit("fullscreen", "0")
+	receive(ctx, t, changes, 1)
+	if got := fullscreen(); got != 0 {
+		t.Fatalf("fullscreen = %d, want 0", got)
+	}
+
+	// lookups run one at a time, so the next request means the previous one
+	// is done. No mode is assumed when it can not be read or the reply is
+	// about another window.
+	s.Emit("fullscreen", "1")
+	receive(ctx, t, requested, 1)
+	release <- "unknown request"
+	s.Emit("fullscreen", "1")
+	receive(ctx, t, requested, 1)
+	if got := fullscreen(); got != 0 {
+		t.Errorf("fullscreen = %d after a failed request, want 0", got)
+	}
+	release <- `{"address": "0xbeef", "fullscreen": 1}`
+	s.Emit("fullscreen", "1")
+	receive(ctx, t, requested, 1)
+	if got := fullscreen(); got != 0 {
+		t.Errorf("fullscreen = %d after a reply for another window", got)
+	}
+	release <- `{"address": "0x5a1f", "fullscreen": 1}`
+	if got := receive(ctx, t, changes, 1)[0]; got != EventFullscreen {
+		t.Fatalf("change = %q, want %q", got, EventFullscreen)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
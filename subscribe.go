@@ -0,0 +1,126 @@
+package hyprland
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// unknownKey is the subscription key of UnknownEvent, which has no fixed
+// event name.
+const unknownKey Event = ""
+
+type subscription struct {
+	id uint64
+	fn func(ctx *EventContext, ev EventValue)
+}
+
+// Subscribe adds fn as a handler for events of type T and returns a function
+// that removes it. Unlike the On* methods, any number of handlers can be added
+// for the same event. It is safe to subscribe and unsubscribe while Listen is
+// running, also from within a handler.
+//
+//	stop := hyprland.Subscribe(l, func(
+//		ctx *hyprland.EventContext,
+//		ev hyprland.OpenWindowEvent,
+//	) {
+//		fmt.Println("opened", ev.Class)
+//	})
+//	defer stop()
+//
+// T must be one of the event structs, e.g. OpenWindowEvent. Subscribe panics
+// for interface and pointer types, as events are never delivered as those.
+// Use SubscribeAll to handle every EventValue.
+func Subscribe[T EventValue](
+	l *EventListener,
+	fn func(ctx *EventContext, ev T),
+) func() {
+	if typ := reflect.TypeFor[T](); typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf(
+			"hyprland: Subscribe needs an event struct type, got %s",
+			typ,
+		))
+	}
+	var zero T
+	key := zero.EventName()
+	if _, ok := any(zero).(UnknownEvent); ok {
+		key = unknownKey
+	}
+	return l.subscribe(key, func(ctx *EventContext, ev EventValue) {
+		fn(ctx, ev.(T))
+	})
+}
+
+// SubscribeAll adds fn as a handler for every event and returns a function
+// that removes it. See Subscribe.
+func (l *EventListener) SubscribeAll(
+	fn func(ctx *EventContext, ev EventValue),
+) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.nextID
+	l.nextID++
+	l.subscriptionsAll = append(l.subscriptionsAll, subscription{id, fn})
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.subscriptionsAll = removeSubscription(l.subscriptionsAll, id)
+	}
+}
+
+func (l *EventListener) subscribe(
+	key Event,
+	fn func(ctx *EventContext, ev EventValue),
+) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.subscriptions == nil {
+		l.subscriptions = map[Event][]subscription{}
+	}
+	id := l.nextID
+	l.nextID++
+	l.subscriptions[key] = append(l.subscriptions[key], subscription{id, fn})
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		subs := removeSubscription(l.subscriptions[key], id)
+		if len(subs) == 0 {
+			delete(l.subscriptions, key)
+			return
+		}
+		l.subscriptions[key] = subs
+	}
+}
+
+// removeSubscription returns subs without id. The slice is copied as
+// dispatchSubscriptions may be iterating the old one.
+func removeSubscription(subs []subscription, id uint64) []subscription {
+	return slices.DeleteFunc(slices.Clone(subs), func(s subscription) bool {
+		return s.id == id
+	})
+}
+
+// dispatchSubscriptions calls the subscriptions for ev. The handlers are
+// called without holding mu, so they may subscribe and unsubscribe.
+func (l *EventListener) dispatchSubscriptions(
+	ctx *EventContext,
+	ev EventValue,
+	call func(*EventContext, HandlerFunc),
+) {
+	key := ev.EventName()
+	if _, ok := ev.(UnknownEvent); ok {
+		key = unknownKey
+	}
+
+	l.mu.Lock()
+	all, subs := l.subscriptionsAll, l.subscriptions[key]
+	l.mu.Unlock()
+
+	for _, s := range slices.Concat(all, subs) {
+		call(ctx, func(ctx *EventContext) { s.fn(ctx, ev) })
+	}
+}
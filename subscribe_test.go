@@ -0,0 +1,84 @@
+package hyprland
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/go-hyprland/hyprlandtest"
+)
+
+func TestSubscribe(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l := NewEventListener()
+	got := make(chan string, 10)
+	stopA := Subscribe(l, func(_ *EventContext, ev OpenLayerEvent) {
+		got <- "a:" + ev.Namespace
+	})
+	Subscribe(l, func(_ *EventContext, ev OpenLayerEvent) {
+		got <- "b:" + ev.Namespace
+	})
+	Subscribe(l, func(_ *EventContext, ev UnknownEvent) {
+		got <- "unknown:" + string(ev.Name)
+	})
+	stopAll := l.SubscribeAll(func(_ *EventContext, ev EventValue) {
+		got <- "all:" + string(ev.EventName())
+	})
+	if !l.HasHandler(EventCloseLayer) {
+		t.Error("HasHandler() = false with SubscribeAll")
+	}
+
+	listen(ctx, t, l)
+	if err := s.WaitListeners(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Emit("openlayer", "waybar")
+	want := []string{"all:openlayer", "a:waybar", "b:waybar"}
+	if lines := receive(ctx, t, got, 3); !slices.Equal(lines, want) {
+		t.Errorf("handlers = %q, want %q", lines, want)
+	}
+
+	stopA()
+	stopAll()
+	s.Emit("openlayer", "rofi")
+	s.Emit("myplugin", "x")
+	want = []string{"b:rofi", "unknown:myplugin"}
+	if lines := receive(ctx, t, got, 2); !slices.Equal(lines, want) {
+		t.Errorf("handlers after unsubscribe = %q, want %q", lines, want)
+	}
+	if l.HasHandler(EventCloseLayer) {
+		t.Error("HasHandler() = true after unsubscribe")
+	}
+}
+
+func TestSubscribeInvalidType(t *testing.T) {
+	tests := []struct {
+		name      string
+		subscribe func(l *EventListener)
+	}{
+		{"interface", func(l *EventListener) {
+			Subscribe(l, func(*EventContext, EventValue) {})
+		}},
+		{"pointer", func(l *EventListener) {
+			Subscribe(l, func(*EventContext, *OpenWindowEvent) {})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.Contains(msg, "needs an event struct type") {
+					t.Errorf("Subscribe() panicked with %q", msg)
+				}
+			}()
+			tt.subscribe(NewEventListener())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package hyprland
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the build information of the running hyprland.
+type Version struct {
+	Branch        string   `json:"branch"`
+	Commit        string   `json:"commit"`
+	Version       string   `json:"version"`
+	Dirty         bool     `json:"dirty"`
+	CommitMessage string   `json:"commit_message"`
+	CommitDate    string   `json:"commit_date"`
+	Tag           string   `json:"tag"`
+	Commits       string   `json:"commits"`
+	Flags         []string `json:"flags"`
+}
+
+// SemVer returns the parsed release version. Older hyprland only reports the
+// git tag, which is used as a fallback.
+func (v Version) SemVer() (SemVer, error) {
+	if v.Version != "" {
+		return ParseSemVer(v.Version)
+	}
+	return ParseSemVer(v.Tag)
+}
+
+// Supports returns if the hyprland release has the given capability. Unknown
+// or unparsable versions are treated as not supporting anything.
+func (v Version) Supports(c Capability) bool {
+	since, ok := capabilities[c]
+	if !ok {
+		return false
+	}
+	sv, err := v.SemVer()
+	if err != nil {
+		return false
+	}
+	return sv.AtLeast(since)
+}
+
+// SemVer is a major.minor.patch release version.
+type SemVer struct {
+	Major, Minor, Patch int
+}
+
+// ParseSemVer parses versions like "0.50.1", "v0.50.1" or
+// "v0.50.1-12-gabcdef". Pre-release and build suffixes are ignored.
+func ParseSemVer(s string) (SemVer, error) {
+	var v SemVer
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(str, "-+ "); i >= 0 {
+		str = str[:i]
+	}
+
+	parts := strings.Split(str, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version: %q", s)
+	}
+
+	nums := [3]int{}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version: %q", s)
+		}
+		nums[i] = n
+	}
+
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// String returns the version as "major.minor.patch".
+func (v SemVer) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 if v is older, equal or newer than o.
+func (v SemVer) Compare(o SemVer) int {
+	a := [3]int{v.Major, v.Minor, v.Patch}
+	b := [3]int{o.Major, o.Minor, o.Patch}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns if v is equal or newer than o.
+func (v SemVer) AtLeast(o SemVer) bool {
+	return v.Compare(o) >= 0
+}
+
+// Capability is a feature that is only available on newer hyprland releases.
+type Capability string
+
+const (
+	// CapWindowTitleV2 is the windowtitlev2 event
+	CapWindowTitleV2 Capability = "windowtitlev2"
+	// CapBellEvent is the bell event
+	CapBellEvent Capability = "bell"
+	// CapFocusedMonV2 is the focusedmonv2 event
+	CapFocusedMonV2 Capability = "focusedmonv2"
+	// CapClientXdgTag is the Client.XdgTag and Client.XdgDescription fields
+	CapClientXdgTag Capability = "client.xdgTag"
+	// CapMonitorDirectScanoutTo is the Monitor.DirectScanoutTo field
+	CapMonitorDirectScanoutTo Capability = "monitor.directScanoutTo"
+)
+
+// capabilities maps a capability to the first release that has it.
+var capabilities = map[Capability]SemVer{
+	CapWindowTitleV2:          {0, 42, 0},
+	CapBellEvent:              {0, 48, 0},
+	CapFocusedMonV2:           {0, 46, 0},
+	CapClientXdgTag:           {0, 48, 0},
+	CapMonitorDirectScanoutTo: {0, 47, 0},
+}
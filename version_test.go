@@ -0,0 +1,45 @@
+package hyprland
+
+import "testing"
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want SemVer
+		err  bool
+	}{
+		{"0.50.1", SemVer{0, 50, 1}, false},
+		{"v0.42.0", SemVer{0, 42, 0}, false},
+		{"v0.49.0-12-gabcdef", SemVer{0, 49, 0}, false},
+		{"0.41", SemVer{0, 41, 0}, false},
+		{"unknown", SemVer{}, true},
+		{"", SemVer{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSemVer(tt.in)
+			if (err != nil) != tt.err {
+				t.Fatalf("ParseSemVer(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSemVer(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionSupports(t *testing.T) {
+	old := Version{Tag: "v0.41.2"}
+	if old.Supports(CapWindowTitleV2) {
+		t.Error("v0.41.2 should not support windowtitlev2")
+	}
+
+	cur := Version{Version: "0.50.1", Tag: "v0.50.1"}
+	if !cur.Supports(CapWindowTitleV2) || !cur.Supports(CapBellEvent) {
+		t.Error("0.50.1 should support windowtitlev2 and bell")
+	}
+	if cur.Supports("nope") {
+		t.Error("unknown capability should not be supported")
+	}
+}
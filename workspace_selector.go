@@ -0,0 +1,308 @@
+package hyprland
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WorkspaceSelector identifies a workspace in dispatchers and configs. Use
+// the Workspace* constructors or ParseWorkspaceSelector to create one.
+type WorkspaceSelector struct {
+	kind workspaceKind
+	// n is the id, offset or index depending on kind
+	n int
+	// name is the workspace name for named and special workspaces
+	name string
+	// onMonitor and next are the 'm' and 'n' flags of empty
+	onMonitor, next bool
+}
+
+type workspaceKind int
+
+const (
+	wsNone workspaceKind = iota
+	wsID
+	wsName
+	wsSpecial
+	wsPrevious
+	wsPreviousPerMonitor
+	wsEmpty
+	// wsRelative is '+n' or '-n', relative to the current workspace id
+	wsRelative
+	// wsMonitor is 'm+n', relative among open workspaces on the monitor
+	wsMonitor
+	// wsMonitorEmpty is 'r+n', like wsMonitor but includes empty workspaces
+	wsMonitorEmpty
+	// wsOpen is 'e+n', relative among open workspaces on all monitors
+	wsOpen
+	// wsMonitorIndex is 'm~n', the nth open workspace on the monitor
+	wsMonitorIndex
+	// wsMonitorEmptyIndex is 'r~n', like wsMonitorIndex with empty ones
+	wsMonitorEmptyIndex
+	// wsOpenIndex is 'e~n', the nth open workspace on all monitors
+	wsOpenIndex
+)
+
+// relativePrefixes are the prefixes of relative kinds. Longer prefixes come
+// first so "m~" is matched before "m".
+var relativePrefixes = []struct {
+	kind   workspaceKind
+	prefix string
+}{
+	{wsMonitorIndex, "m~"},
+	{wsMonitorEmptyIndex, "r~"},
+	{wsOpenIndex, "e~"},
+	{wsMonitor, "m"},
+	{wsMonitorEmpty, "r"},
+	{wsOpen, "e"},
+}
+
+var (
+	// WorkspacePrevious selects the previously focused workspace
+	WorkspacePrevious = WorkspaceSelector{kind: wsPrevious}
+	// WorkspacePreviousPerMonitor selects the previous workspace on the
+	// current monitor
+	WorkspacePreviousPerMonitor = WorkspaceSelector{kind: wsPreviousPerMonitor}
+)
+
+// WorkspaceByID selects a workspace by its id.
+func WorkspaceByID(id int) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsID, n: id}
+}
+
+// WorkspaceByName selects a workspace by its name.
+func WorkspaceByName(name string) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsName, name: name}
+}
+
+// WorkspaceSpecial selects a special workspace. Empty name selects the
+// default special workspace.
+func WorkspaceSpecial(name string) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsSpecial, name: name}
+}
+
+// WorkspaceEmpty selects the first empty workspace. onMonitor limits it to the
+// current monitor and next selects the next empty workspace after the current
+// one.
+func WorkspaceEmpty(onMonitor, next bool) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsEmpty, onMonitor: onMonitor, next: next}
+}
+
+// WorkspaceRelative selects the workspace with id offset from the current one.
+func WorkspaceRelative(offset int) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsRelative, n: offset}
+}
+
+// WorkspaceMonitorRelative selects a workspace relative to the current one on
+// the current monitor. includeEmpty also counts empty workspaces.
+func WorkspaceMonitorRelative(offset int, includeEmpty bool) WorkspaceSelector {
+	if includeEmpty {
+		return WorkspaceSelector{kind: wsMonitorEmpty, n: offset}
+	}
+	return WorkspaceSelector{kind: wsMonitor, n: offset}
+}
+
+// WorkspaceOpenRelative selects an open workspace relative to the current one
+// on all monitors.
+func WorkspaceOpenRelative(offset int) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsOpen, n: offset}
+}
+
+// WorkspaceMonitorIndex selects the nth (starting from 1) workspace on the
+// current monitor. includeEmpty also counts empty workspaces.
+func WorkspaceMonitorIndex(n int, includeEmpty bool) WorkspaceSelector {
+	if includeEmpty {
+		return WorkspaceSelector{kind: wsMonitorEmptyIndex, n: n}
+	}
+	return WorkspaceSelector{kind: wsMonitorIndex, n: n}
+}
+
+// WorkspaceOpenIndex selects the nth (starting from 1) open workspace on all
+// monitors.
+func WorkspaceOpenIndex(n int) WorkspaceSelector {
+	return WorkspaceSelector{kind: wsOpenIndex, n: n}
+}
+
+// WorkspaceFrom selects the given workspace. Special workspaces are selected
+// by name, others by id.
+func WorkspaceFrom(w SimpleWorkspace) WorkspaceSelector {
+	if name, ok := strings.CutPrefix(w.Name, "special:"); ok {
+		if name == "special" {
+			name = ""
+		}
+		return WorkspaceSpecial(name)
+	}
+	if w.ID > 0 {
+		return WorkspaceByID(int(w.ID))
+	}
+	return WorkspaceByName(w.Name)
+}
+
+// WorkspaceOf selects the given workspace. See WorkspaceFrom.
+func WorkspaceOf(w Workspace) WorkspaceSelector {
+	return WorkspaceFrom(SimpleWorkspace{ID: w.ID, Name: w.Name})
+}
+
+// ParseWorkspaceSelector parses a workspace in hyprland's syntax, e.g. "3",
+// "r+1", "name:web" or "special:scratch".
+func ParseWorkspaceSelector(s string) (WorkspaceSelector, error) {
+	var w WorkspaceSelector
+	switch {
+	case s == "":
+		return w, errors.New("empty workspace selector")
+	case s == "previous":
+		return WorkspacePrevious, nil
+	case s == "previous_per_monitor":
+		return WorkspacePreviousPerMonitor, nil
+	case s == "special":
+		return WorkspaceSpecial(""), nil
+	case strings.HasPrefix(s, "special:"):
+		w = WorkspaceSpecial(strings.TrimPrefix(s, "special:"))
+	case strings.HasPrefix(s, "name:"):
+		w = WorkspaceByName(strings.TrimPrefix(s, "name:"))
+	case strings.HasPrefix(s, "empty"):
+		// only the order String gives is accepted, so parsing round-trips
+		flags := strings.TrimPrefix(s, "empty")
+		if flags != "" && flags != "m" && flags != "n" && flags != "mn" {
+			return w, fmt.Errorf("invalid empty workspace flags: %q", s)
+		}
+		w = WorkspaceEmpty(
+			strings.Contains(flags, "m"),
+			strings.Contains(flags, "n"),
+		)
+	case s[0] == '+' || s[0] == '-':
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return w, fmt.Errorf("invalid relative workspace: %q", s)
+		}
+		w = WorkspaceRelative(n)
+	case len(s) > 2 && strings.ContainsRune("mre", rune(s[0])):
+		kind, n, err := parseRelativeWorkspace(s)
+		if err != nil {
+			return w, err
+		}
+		w = WorkspaceSelector{kind: kind, n: n}
+	default:
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return w, fmt.Errorf("invalid workspace selector: %q", s)
+		}
+		w = WorkspaceByID(id)
+	}
+	return w, w.Validate()
+}
+
+func parseRelativeWorkspace(s string) (workspaceKind, int, error) {
+	for _, r := range relativePrefixes {
+		rest, ok := strings.CutPrefix(s, r.prefix)
+		if !ok {
+			continue
+		}
+		signed := rest != "" && (rest[0] == '+' || rest[0] == '-')
+		if signed == isIndexKind(r.kind) {
+			return wsNone, 0, fmt.Errorf("invalid workspace offset: %q", s)
+		}
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			return wsNone, 0, fmt.Errorf("invalid workspace offset: %q", s)
+		}
+		return r.kind, n, nil
+	}
+	return wsNone, 0, fmt.Errorf("invalid workspace selector: %q", s)
+}
+
+func isIndexKind(kind workspaceKind) bool {
+	return kind == wsMonitorIndex || kind == wsMonitorEmptyIndex ||
+		kind == wsOpenIndex
+}
+
+func relativePrefix(kind workspaceKind) string {
+	for _, r := range relativePrefixes {
+		if r.kind == kind {
+			return r.prefix
+		}
+	}
+	return ""
+}
+
+// IsZero returns if w is the zero WorkspaceSelector.
+func (w WorkspaceSelector) IsZero() bool {
+	return w == WorkspaceSelector{}
+}
+
+// Validate checks that the selector can be sent to hyprland.
+func (w WorkspaceSelector) Validate() error {
+	switch w.kind {
+	case wsNone:
+		return errors.New("workspace selector is required")
+	case wsID:
+		if w.n <= 0 {
+			return fmt.Errorf("invalid workspace id: %d", w.n)
+		}
+	case wsName:
+		if w.name == "" {
+			return errors.New("workspace name can not be empty")
+		}
+	case wsMonitorIndex, wsMonitorEmptyIndex, wsOpenIndex:
+		if w.n <= 0 {
+			return fmt.Errorf("invalid workspace index: %d", w.n)
+		}
+	}
+	if strings.ContainsAny(w.name, ",;\n") {
+		return fmt.Errorf("invalid workspace name: %q", w.name)
+	}
+	return nil
+}
+
+// String returns the selector in hyprland's syntax.
+func (w WorkspaceSelector) String() string {
+	switch w.kind {
+	case wsID:
+		return strconv.Itoa(w.n)
+	case wsName:
+		return "name:" + w.name
+	case wsSpecial:
+		if w.name == "" {
+			return "special"
+		}
+		return "special:" + w.name
+	case wsPrevious:
+		return "previous"
+	case wsPreviousPerMonitor:
+		return "previous_per_monitor"
+	case wsEmpty:
+		s := "empty"
+		if w.onMonitor {
+			s += "m"
+		}
+		if w.next {
+			s += "n"
+		}
+		return s
+	case wsRelative:
+		return signed(w.n)
+	case wsMonitor, wsMonitorEmpty, wsOpen:
+		return relativePrefix(w.kind) + signed(w.n)
+	case wsMonitorIndex, wsMonitorEmptyIndex, wsOpenIndex:
+		return relativePrefix(w.kind) + strconv.Itoa(w.n)
+	default:
+		return ""
+	}
+}
+
+// arg validates w and returns it as a dispatcher argument.
+func (w WorkspaceSelector) arg() (string, error) {
+	if err := w.Validate(); err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
+
+func signed(n int) string {
+	if n >= 0 {
+		return "+" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
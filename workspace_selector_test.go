@@ -0,0 +1,49 @@
+package hyprland
+
+import "testing"
+
+func TestParseWorkspaceSelector(t *testing.T) {
+	for _, s := range []string{
+		"1", "42", "+1", "-2", "m+1", "m-1", "r+1", "r-3", "e+1", "e-1",
+		"m~1", "r~2", "e~3", "name:web", "special", "special:scratch",
+		"previous", "previous_per_monitor", "empty", "emptym", "emptyn",
+		"emptymn",
+	} {
+		t.Run(s, func(t *testing.T) {
+			w, err := ParseWorkspaceSelector(s)
+			if err != nil {
+				t.Fatalf("ParseWorkspaceSelector(%q) failed: %v", s, err)
+			}
+			if w.String() != s {
+				t.Errorf("ParseWorkspaceSelector(%q).String() = %q", s, w.String())
+			}
+		})
+	}
+
+	for _, s := range []string{
+		"", "0", "-", "m+", "m1", "m~0", "m~-1", "e~+1", "x+1", "emptyx",
+		"emptynm", "emptymm", "emptynn", "name:", "web",
+	} {
+		if _, err := ParseWorkspaceSelector(s); err == nil {
+			t.Errorf("ParseWorkspaceSelector(%q) should fail", s)
+		}
+	}
+}
+
+func TestWorkspaceFrom(t *testing.T) {
+	tests := []struct {
+		in   SimpleWorkspace
+		want string
+	}{
+		{SimpleWorkspace{ID: 3, Name: "3"}, "3"},
+		{SimpleWorkspace{ID: -98, Name: "special:scratch"}, "special:scratch"},
+		{SimpleWorkspace{ID: -99, Name: "special:special"}, "special"},
+		{SimpleWorkspace{ID: -1337, Name: "web"}, "name:web"},
+	}
+
+	for _, tt := range tests {
+		if got := WorkspaceFrom(tt.in).String(); got != tt.want {
+			t.Errorf("WorkspaceFrom(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}